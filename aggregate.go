@@ -0,0 +1,74 @@
+package polyrat
+
+// Sum returns the sum of a set of codes generated with the given parameters.
+// The coefficients grow with the number of codes, as bounded by EstimateGrowth with n - 1
+// additions for the digit set of the parameters, and ErrCoefficientOverflow is returned
+// if they do not fit in a 64-bit integer.
+func Sum(codes [][]int64, params *Parameters) (*Encoded, error) {
+	if len(codes) == 0 {
+		return nil, ErrCodesAreEmpty
+	}
+	return aggregate(codes, nil, params)
+}
+
+// WeightedSum returns the sum of a set of codes multiplied by rational weights.
+// Weights are encoded with the same parameters as the codes, so the scale of the result is 2 x |p|.
+// Coefficients grow as in InnerProduct.
+func WeightedSum(codes [][]int64, weights []float64, params *Parameters) (*Encoded, error) {
+	if len(codes) != len(weights) {
+		return nil, ErrVectorLengthsAreDifferent
+	}
+	ws := make([][]int64, len(weights))
+	for i, w := range weights {
+		var err error
+		ws[i], err = Encode(w, params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return InnerProduct(codes, ws, params)
+}
+
+// InnerProduct returns the inner product of two vectors of codes generated with the given parameters.
+// The scale of the result is 2 x |p|. The coefficients of every product are bounded by EstimateGrowth
+// with one multiplication for the digit set of the parameters, those of the result by n times this bound
+// for vectors of n codes, and ErrCoefficientOverflow is returned if they do not fit in a 64-bit integer.
+func InnerProduct(a, b [][]int64, params *Parameters) (*Encoded, error) {
+	if len(a) != len(b) {
+		return nil, ErrVectorLengthsAreDifferent
+	}
+	if len(a) == 0 {
+		return nil, ErrCodesAreEmpty
+	}
+	return aggregate(a, b, params)
+}
+
+// aggregate adds the codes of a, multiplied by the codes of b when b is given.
+func aggregate(a, b [][]int64, params *Parameters) (*Encoded, error) {
+	var acc *Encoded
+	for i := range a {
+		x, err := NewEncodedFromCode(a[i], params)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			y, err := NewEncodedFromCode(b[i], params)
+			if err != nil {
+				return nil, err
+			}
+			x, err = x.Mul(y)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if acc == nil {
+			acc = x
+			continue
+		}
+		acc, err = acc.Add(x)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
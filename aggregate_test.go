@@ -0,0 +1,121 @@
+package polyrat
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// aggregateInputs encodes a set of rationals and returns their codes and exact values.
+func aggregateInputs(t *testing.T, r []float64, params *Parameters) ([][]int64, []*big.Rat) {
+	var codes [][]int64
+	var fs []*big.Rat
+	for i := 0; i < len(r); i++ {
+		c, err := Encode(r[i], params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		codes = append(codes, c)
+		fs = append(fs, rationalToFraction(r[i], params))
+	}
+	return codes, fs
+}
+
+func TestSum(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 5, 64)
+	if err != nil {
+		t.Error(err)
+	}
+	r := []float64{123.45, -67.89, 0.01, 5555.55, -4444.44, 98765.43, -0.99}
+	codes, fs := aggregateInputs(t, r, params)
+	enc, err := Sum(codes, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Exact sum.
+	e := new(big.Rat)
+	for _, f := range fs {
+		e.Add(e, f)
+	}
+	if enc.Rat().Cmp(e) != 0 {
+		t.Errorf("expected sum %s but got %s", e.FloatString(2), enc.Rat().FloatString(2))
+	}
+	dr, err := enc.Decode()
+	if err != nil {
+		t.Error(err)
+	}
+	// The float64 closest to -4444.44 is truncated to -4444.43.
+	if dr != 99931.13 {
+		t.Errorf("expected sum %f but got %f", 99931.13, dr)
+	}
+	// Check that an error is thrown when a coefficient of the sum does not fit in 64 bits.
+	large := make([]int64, 64)
+	large[0] = math.MaxInt64
+	_, err = Sum([][]int64{large, large}, params)
+	if !errors.Is(err, ErrCoefficientOverflow) {
+		t.Errorf("expected error %v but got %v", ErrCoefficientOverflow, err)
+	}
+	// Check that an error is thrown when there are no codes.
+	_, err = Sum(nil, params)
+	if !errors.Is(err, ErrCodesAreEmpty) {
+		t.Errorf("expected error %v but got %v", ErrCodesAreEmpty, err)
+	}
+}
+
+func TestInnerProduct(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 5, 64)
+	if err != nil {
+		t.Error(err)
+	}
+	a, fa := aggregateInputs(t, []float64{1.5, -2.25, 100.01, -0.07}, params)
+	b, fb := aggregateInputs(t, []float64{-3.5, 4.75, 0.99, 1234.56}, params)
+	enc, err := InnerProduct(a, b, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc.Scale() != 4 {
+		t.Errorf("expected scale %d but got %d", 4, enc.Scale())
+	}
+	// Exact inner product.
+	e := new(big.Rat)
+	for i := range fa {
+		e.Add(e, new(big.Rat).Mul(fa[i], fb[i]))
+	}
+	if enc.Rat().Cmp(e) != 0 {
+		t.Errorf("expected inner product %s but got %s", e.FloatString(4), enc.Rat().FloatString(4))
+	}
+	// Check that an error is thrown when the vectors have different lengths.
+	_, err = InnerProduct(a, b[1:], params)
+	if !errors.Is(err, ErrVectorLengthsAreDifferent) {
+		t.Errorf("expected error %v but got %v", ErrVectorLengthsAreDifferent, err)
+	}
+}
+
+func TestWeightedSum(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 5, 64)
+	if err != nil {
+		t.Error(err)
+	}
+	codes, _ := aggregateInputs(t, []float64{10.0, 20.0, 30.5, -40.25}, params)
+	// Weights of a mean: 1/4 = 0.25.
+	enc, err := WeightedSum(codes, []float64{0.25, 0.25, 0.25, 0.25}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dr, err := enc.Decode()
+	if err != nil {
+		t.Error(err)
+	}
+	if dr != 5.0625 {
+		t.Errorf("expected weighted sum %f but got %f", 5.0625, dr)
+	}
+	// Check that an error is thrown when a weight is not in the message space.
+	_, err = WeightedSum(codes, []float64{1, 1, 1, 1e9}, params)
+	if !errors.Is(err, ErrNumeratorIsNotInTheMessageSpaceRange) {
+		t.Errorf("expected error %v but got %v", ErrNumeratorIsNotInTheMessageSpaceRange, err)
+	}
+}
@@ -0,0 +1,239 @@
+package polyrat
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Plaintext is a plaintext in the representation of a backend,
+// for instance unsigned coefficients modulo t, NTT values or RNS limbs.
+type Plaintext interface{}
+
+// PlaintextBackend converts codes to and from the plaintexts of a homomorphic encryption library.
+// Plaintexts are elements of Z_t[X]/(X^d + 1), so additions and multiplications of plaintexts
+// should match the operations of Polynomial as long as the coefficients are in (-t/2, t/2].
+// Adapters can check their implementation with the conformance suite of package backendtest.
+type PlaintextBackend interface {
+	// Name returns the name of the backend.
+	Name() string
+	// Degree returns the degree of the ring.
+	Degree() int
+	// Modulus returns the plaintext modulus t.
+	Modulus() uint64
+	// FromCode converts a code into a plaintext.
+	FromCode(code []int64) (Plaintext, error)
+	// ToCode converts a plaintext into a code, with coefficients in (-t/2, t/2].
+	ToCode(pt Plaintext) ([]int64, error)
+	// Add returns the sum of two plaintexts.
+	Add(a, b Plaintext) (Plaintext, error)
+	// Mul returns the product of two plaintexts.
+	Mul(a, b Plaintext) (Plaintext, error)
+}
+
+// ReferenceBackend is the in-memory reference backend.
+// Its plaintexts are slices of d unsigned coefficients in [0, t).
+type ReferenceBackend struct {
+	d int    // d is the degree of the ring.
+	t uint64 // t is the plaintext modulus.
+}
+
+// NewReferenceBackend creates the reference backend of the ring Z_t[X]/(X^d + 1).
+func NewReferenceBackend(d int, t uint64) (*ReferenceBackend, error) {
+	if d < 1 {
+		return nil, ErrDIsLessThanOne
+	}
+	if !isPowerOfTwo(d) {
+		return nil, ErrDIsNotAPowerOfTwo
+	}
+	if t < 2 || bits.Len64(t) > MaxNTTModulusBits {
+		return nil, ErrPlaintextModulusIsInvalid
+	}
+	return &ReferenceBackend{d: d, t: t}, nil
+}
+
+// Name returns the name of the reference backend.
+func (backend *ReferenceBackend) Name() string {
+	return "reference"
+}
+
+// Getter for degree.
+func (backend *ReferenceBackend) Degree() int {
+	return backend.d
+}
+
+// Getter for modulus.
+func (backend *ReferenceBackend) Modulus() uint64 {
+	return backend.t
+}
+
+// FromCode reduces the coefficients of a code modulo t.
+func (backend *ReferenceBackend) FromCode(code []int64) (Plaintext, error) {
+	if len(code) != backend.d {
+		return nil, ErrCodeDegreeIsDifferentFromDegree
+	}
+	return reduceCoefficients(code, backend.t), nil
+}
+
+// ToCode lifts the coefficients of a plaintext to (-t/2, t/2].
+func (backend *ReferenceBackend) ToCode(pt Plaintext) ([]int64, error) {
+	a, err := backend.plaintext(pt)
+	if err != nil {
+		return nil, err
+	}
+	return centeredLift(a, backend.t), nil
+}
+
+// Add returns the sum of two plaintexts modulo t.
+func (backend *ReferenceBackend) Add(a, b Plaintext) (Plaintext, error) {
+	x, err := backend.plaintext(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := backend.plaintext(b)
+	if err != nil {
+		return nil, err
+	}
+	s := make([]uint64, backend.d)
+	for i := range s {
+		s[i] = addMod(x[i], y[i], backend.t)
+	}
+	return s, nil
+}
+
+// Mul returns the negacyclic product of two plaintexts modulo t.
+func (backend *ReferenceBackend) Mul(a, b Plaintext) (Plaintext, error) {
+	x, err := backend.plaintext(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := backend.plaintext(b)
+	if err != nil {
+		return nil, err
+	}
+	// Exact product of the centered coefficients, reduced modulo t.
+	c := mulKronecker(centeredLift(x, backend.t), centeredLift(y, backend.t))
+	bt := new(big.Int).SetUint64(backend.t)
+	p := make([]uint64, backend.d)
+	for i := range p {
+		p[i] = c[i].Mod(c[i], bt).Uint64()
+	}
+	return p, nil
+}
+
+// plaintext checks that a plaintext belongs to the backend.
+func (backend *ReferenceBackend) plaintext(pt Plaintext) ([]uint64, error) {
+	a, ok := pt.([]uint64)
+	if !ok || len(a) != backend.d {
+		return nil, ErrPlaintextIsInvalid
+	}
+	return a, nil
+}
+
+// NTTBackend is a backend whose plaintexts are in the NTT domain of an NTT-friendly prime t.
+// Its plaintexts are slices of d values in [0, t), in the bit-reversed order of NTT.Forward,
+// so additions and multiplications are coefficient-wise.
+type NTTBackend struct {
+	ntt *NTT // ntt is the transform of the ring.
+}
+
+// NewNTTBackend creates a backend in the NTT domain of the ring Z_t[X]/(X^d + 1).
+func NewNTTBackend(d int, t uint64) (*NTTBackend, error) {
+	ntt, err := NewNTT(d, t)
+	if err != nil {
+		return nil, err
+	}
+	return &NTTBackend{ntt: ntt}, nil
+}
+
+// Name returns the name of the NTT backend.
+func (backend *NTTBackend) Name() string {
+	return "ntt"
+}
+
+// Getter for degree.
+func (backend *NTTBackend) Degree() int {
+	return backend.ntt.d
+}
+
+// Getter for modulus.
+func (backend *NTTBackend) Modulus() uint64 {
+	return backend.ntt.q
+}
+
+// FromCode reduces the coefficients of a code modulo t and transforms them into the NTT domain.
+func (backend *NTTBackend) FromCode(code []int64) (Plaintext, error) {
+	if len(code) != backend.ntt.d {
+		return nil, ErrCodeDegreeIsDifferentFromDegree
+	}
+	a := reduceCoefficients(code, backend.ntt.q)
+	backend.ntt.Forward(a)
+	return a, nil
+}
+
+// ToCode transforms a plaintext back into its coefficients and lifts them to (-t/2, t/2].
+func (backend *NTTBackend) ToCode(pt Plaintext) ([]int64, error) {
+	a, err := backend.plaintext(pt)
+	if err != nil {
+		return nil, err
+	}
+	c := make([]uint64, len(a))
+	copy(c, a)
+	backend.ntt.Inverse(c)
+	return centeredLift(c, backend.ntt.q), nil
+}
+
+// Add returns the coefficient-wise sum of two plaintexts.
+func (backend *NTTBackend) Add(a, b Plaintext) (Plaintext, error) {
+	x, err := backend.plaintext(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := backend.plaintext(b)
+	if err != nil {
+		return nil, err
+	}
+	s := make([]uint64, len(x))
+	for i := range s {
+		s[i] = addMod(x[i], y[i], backend.ntt.q)
+	}
+	return s, nil
+}
+
+// Mul returns the coefficient-wise product of two plaintexts.
+func (backend *NTTBackend) Mul(a, b Plaintext) (Plaintext, error) {
+	x, err := backend.plaintext(a)
+	if err != nil {
+		return nil, err
+	}
+	y, err := backend.plaintext(b)
+	if err != nil {
+		return nil, err
+	}
+	p := make([]uint64, len(x))
+	for i := range p {
+		p[i] = mulMod(x[i], y[i], backend.ntt.q)
+	}
+	return p, nil
+}
+
+// plaintext checks that a plaintext belongs to the backend.
+func (backend *NTTBackend) plaintext(pt Plaintext) ([]uint64, error) {
+	a, ok := pt.([]uint64)
+	if !ok || len(a) != backend.ntt.d {
+		return nil, ErrPlaintextIsInvalid
+	}
+	return a, nil
+}
+
+// centeredLift maps coefficients in [0, t) to (-t/2, t/2].
+func centeredLift(a []uint64, t uint64) []int64 {
+	c := make([]int64, len(a))
+	for i, v := range a {
+		if v > t/2 {
+			c[i] = -int64(t - v)
+		} else {
+			c[i] = int64(v)
+		}
+	}
+	return c
+}
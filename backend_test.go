@@ -0,0 +1,73 @@
+package polyrat
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewReferenceBackend(t *testing.T) {
+	tests := []struct {
+		d   int
+		t   uint64
+		err error
+	}{
+		{16, 257, nil},
+		{0, 257, ErrDIsLessThanOne},
+		{12, 257, ErrDIsNotAPowerOfTwo},
+		{16, 1, ErrPlaintextModulusIsInvalid},
+		{16, 1 << 63, ErrPlaintextModulusIsInvalid},
+	}
+	for _, test := range tests {
+		_, err := NewReferenceBackend(test.d, test.t)
+		if !errors.Is(err, test.err) {
+			t.Errorf("d=%d, t=%d: expected error %v but got %v", test.d, test.t, test.err, err)
+		}
+	}
+}
+
+func TestBackendPlaintextIsInvalid(t *testing.T) {
+	reference, err := NewReferenceBackend(8, 257)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ntt, err := NewNTTBackend(8, 257)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, backend := range []PlaintextBackend{reference, ntt} {
+		// Plaintexts of another type or of another degree are rejected.
+		for _, pt := range []Plaintext{[]int64{1, 2, 3, 4, 5, 6, 7, 8}, make([]uint64, 16), nil} {
+			if _, err := backend.ToCode(pt); !errors.Is(err, ErrPlaintextIsInvalid) {
+				t.Errorf("%s: expected error %v but got %v", backend.Name(), ErrPlaintextIsInvalid, err)
+			}
+			if _, err := backend.Add(pt, pt); !errors.Is(err, ErrPlaintextIsInvalid) {
+				t.Errorf("%s: expected error %v but got %v", backend.Name(), ErrPlaintextIsInvalid, err)
+			}
+			if _, err := backend.Mul(pt, pt); !errors.Is(err, ErrPlaintextIsInvalid) {
+				t.Errorf("%s: expected error %v but got %v", backend.Name(), ErrPlaintextIsInvalid, err)
+			}
+		}
+	}
+}
+
+func TestReferenceBackendToCode(t *testing.T) {
+	backend, err := NewReferenceBackend(4, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := backend.FromCode([]int64{-1, 5, 6, -23})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Coefficients are lifted to (-t/2, t/2].
+	c, err := backend.ToCode(pt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := []int64{-1, 5, -4, -3}
+	for i := range e {
+		if c[i] != e[i] {
+			t.Fatalf("expected %v but got %v", e, c)
+		}
+	}
+}
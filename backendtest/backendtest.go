@@ -0,0 +1,138 @@
+// Package backendtest implements a conformance suite for plaintext backends.
+//
+// An adapter for a homomorphic encryption library runs the suite from its own tests:
+//
+//	func TestBackend(t *testing.T) {
+//		backendtest.Run(t, NewAdapter(), params)
+//	}
+package backendtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Algemetric/polyrat"
+)
+
+// Rationals are the rationals encoded by the suite.
+var Rationals = []float64{0, 1, -1, 0.01, -0.01, 12.34, -56.78, 99.99, -0.5}
+
+// Run checks that a backend converts codes of the given parameters to plaintexts and back,
+// and that additions and multiplications of plaintexts match those of polyrat.Polynomial
+// with coefficients reduced to (-t/2, t/2].
+func Run(t *testing.T, backend polyrat.PlaintextBackend, params *polyrat.Parameters) {
+	t.Helper()
+	if backend.Degree() != params.Degree() {
+		t.Fatalf("%s: backend degree %d is different from the parameter degree %d", backend.Name(), backend.Degree(), params.Degree())
+	}
+	codes := make([][]int64, len(Rationals))
+	for i, r := range Rationals {
+		c, err := polyrat.Encode(r, params)
+		if err != nil {
+			t.Fatalf("%s: %v", backend.Name(), err)
+		}
+		codes[i] = c
+	}
+	t.Run("RoundTrip", func(t *testing.T) {
+		for i, c := range codes {
+			pt, err := backend.FromCode(c)
+			if err != nil {
+				t.Fatalf("%s: %v", backend.Name(), err)
+			}
+			rc, err := backend.ToCode(pt)
+			if err != nil {
+				t.Fatalf("%s: %v", backend.Name(), err)
+			}
+			checkCode(t, backend, Rationals[i], c, rc)
+		}
+	})
+	t.Run("Add", func(t *testing.T) {
+		testOperation(t, backend, params, codes, backend.Add, (*polyrat.Polynomial).Add)
+	})
+	t.Run("Mul", func(t *testing.T) {
+		testOperation(t, backend, params, codes, backend.Mul, (*polyrat.Polynomial).Mul)
+	})
+	t.Run("Degree", func(t *testing.T) {
+		_, err := backend.FromCode(make([]int64, 2*params.Degree()))
+		if !errors.Is(err, polyrat.ErrCodeDegreeIsDifferentFromDegree) {
+			t.Errorf("%s: expected error %v but got %v", backend.Name(), polyrat.ErrCodeDegreeIsDifferentFromDegree, err)
+		}
+	})
+}
+
+// testOperation checks a plaintext operation against the same operation on polynomials.
+func testOperation(t *testing.T, backend polyrat.PlaintextBackend, params *polyrat.Parameters, codes [][]int64,
+	op func(a, b polyrat.Plaintext) (polyrat.Plaintext, error),
+	ref func(a, b *polyrat.Polynomial) (*polyrat.Polynomial, error)) {
+	t.Helper()
+	for i, a := range codes {
+		for j, b := range codes {
+			pa, err := backend.FromCode(a)
+			if err != nil {
+				t.Fatalf("%s: %v", backend.Name(), err)
+			}
+			pb, err := backend.FromCode(b)
+			if err != nil {
+				t.Fatalf("%s: %v", backend.Name(), err)
+			}
+			pc, err := op(pa, pb)
+			if err != nil {
+				t.Fatalf("%s: %v", backend.Name(), err)
+			}
+			c, err := backend.ToCode(pc)
+			if err != nil {
+				t.Fatalf("%s: %v", backend.Name(), err)
+			}
+			x, err := polyrat.NewPolynomialFromCode(a, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			y, err := polyrat.NewPolynomialFromCode(b, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			z, err := ref(x, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalModulo(c, z.Coefficients(), backend.Modulus()) {
+				t.Errorf("%s: operation on %v and %v: expected %v but got %v",
+					backend.Name(), Rationals[i], Rationals[j], z.Coefficients(), c)
+			}
+		}
+	}
+}
+
+// checkCode checks that a code converted back from a plaintext is the original code reduced to (-t/2, t/2].
+func checkCode(t *testing.T, backend polyrat.PlaintextBackend, r float64, expected, got []int64) {
+	t.Helper()
+	if !equalModulo(got, expected, backend.Modulus()) {
+		t.Errorf("%s: code of %v: expected %v but got %v", backend.Name(), r, expected, got)
+	}
+}
+
+// equalModulo reports whether got is expected with coefficients reduced to (-t/2, t/2].
+func equalModulo(got, expected []int64, t uint64) bool {
+	if len(got) != len(expected) {
+		return false
+	}
+	for i, e := range expected {
+		if got[i] != centered(e, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// centered reduces a coefficient to (-t/2, t/2].
+func centered(c int64, t uint64) int64 {
+	m := uint64(c)
+	if c < 0 {
+		m = t - uint64(-c)%t
+	}
+	m %= t
+	if m > t/2 {
+		return -int64(t - m)
+	}
+	return int64(m)
+}
@@ -0,0 +1,38 @@
+package backendtest
+
+import (
+	"testing"
+
+	"github.com/Algemetric/polyrat"
+)
+
+func TestReferenceBackend(t *testing.T) {
+	params, err := polyrat.NewParameters(-2, 4, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A large modulus and a modulus smaller than the product coefficients.
+	for _, m := range []uint64{1 << 40, 257} {
+		backend, err := polyrat.NewReferenceBackend(16, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		Run(t, backend, params)
+	}
+}
+
+func TestNTTBackend(t *testing.T) {
+	params, err := polyrat.NewParameters(-2, 4, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primes, err := polyrat.GenerateNTTPrimes(16, 30, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend, err := polyrat.NewNTTBackend(16, primes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	Run(t, backend, params)
+}
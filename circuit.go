@@ -0,0 +1,213 @@
+package polyrat
+
+import (
+	"math/big"
+)
+
+// Gate types of a circuit.
+const (
+	GateAdd         = iota // GateAdd adds two wires.
+	GateMul                // GateMul multiplies two wires.
+	GateMulConstant        // GateMulConstant multiplies a wire by a rational constant.
+)
+
+// Gate is an operation of a circuit.
+// Every gate produces a new wire, numbered after the inputs and the previous gates.
+type Gate struct {
+	Type     int     // Type is the operation of the gate.
+	Left     int     // Left is the first operand wire.
+	Right    int     // Right is the second operand wire, unused by constant gates.
+	Constant float64 // Constant is the factor of constant gates.
+}
+
+// Circuit describes a computation over encoded inputs with additions,
+// multiplications and multiplications by constants.
+// Wires 0 to inputs - 1 are the inputs, and wire inputs + i is the output of gate i.
+type Circuit struct {
+	inputs int    // inputs is the number of input wires.
+	gates  []Gate // gates are the operations, in evaluation order.
+}
+
+// NewCircuit creates an empty circuit with the given number of inputs.
+func NewCircuit(inputs int) *Circuit {
+	circuit := new(Circuit)
+	circuit.inputs = inputs
+	return circuit
+}
+
+// Add appends the addition of two wires and returns the output wire.
+func (circuit *Circuit) Add(left, right int) int {
+	return circuit.append(Gate{Type: GateAdd, Left: left, Right: right})
+}
+
+// Mul appends the multiplication of two wires and returns the output wire.
+func (circuit *Circuit) Mul(left, right int) int {
+	return circuit.append(Gate{Type: GateMul, Left: left, Right: right})
+}
+
+// MulConstant appends the multiplication of a wire by a constant and returns the output wire.
+// The constant is encoded with the same parameters as the inputs.
+func (circuit *Circuit) MulConstant(wire int, constant float64) int {
+	return circuit.append(Gate{Type: GateMulConstant, Left: wire, Constant: constant})
+}
+
+// Getter for inputs.
+func (circuit *Circuit) Inputs() int {
+	return circuit.inputs
+}
+
+// Gates returns a copy of the gates of the circuit.
+func (circuit *Circuit) Gates() []Gate {
+	gates := make([]Gate, len(circuit.gates))
+	copy(gates, circuit.gates)
+	return gates
+}
+
+// append adds a gate and returns its output wire.
+func (circuit *Circuit) append(gate Gate) int {
+	circuit.gates = append(circuit.gates, gate)
+	return circuit.inputs + len(circuit.gates) - 1
+}
+
+// validate checks that every gate only uses inputs or outputs of previous gates.
+func (circuit *Circuit) validate() error {
+	for i, gate := range circuit.gates {
+		// Wires available to gate i.
+		w := circuit.inputs + i
+		if gate.Left < 0 || gate.Left >= w {
+			return ErrCircuitWireIsInvalid
+		}
+		switch gate.Type {
+		case GateAdd, GateMul:
+			if gate.Right < 0 || gate.Right >= w {
+				return ErrCircuitWireIsInvalid
+			}
+		case GateMulConstant:
+		default:
+			return ErrCircuitGateIsInvalid
+		}
+	}
+	return nil
+}
+
+// SimulationFailure describes the first gate whose result is not decoded correctly.
+type SimulationFailure struct {
+	Gate     int      // Gate is the index of the gate, or -1 if an input could not be encoded.
+	Wire     int      // Wire is the output wire of the gate, or the input wire.
+	Expected *big.Rat // Expected is the exact reference value, nil if it could not be computed.
+	Got      *big.Rat // Got is the decoded value, nil if the gate could not be evaluated.
+	Err      error    // Err is the reason of the failure, nil if the decoded value is only different.
+}
+
+// SimulationReport contains the values of every wire of a simulated circuit.
+type SimulationReport struct {
+	Expected []*big.Rat         // Expected are the exact values of the wires computed on the raw inputs.
+	Decoded  []float64          // Decoded are the decoded values of the wires up to the failure.
+	Encoded  []*Encoded         // Encoded are the encoded values of the wires up to the failure.
+	Failure  *SimulationFailure // Failure is the first failure, nil if every wire is correct.
+}
+
+// Ok checks if every wire of the circuit was decoded correctly.
+func (report *SimulationReport) Ok() bool {
+	return report.Failure == nil
+}
+
+// Simulate runs the circuit on plaintext codes and checks every intermediate decoding.
+// Inputs are encoded with the given parameters and every wire is compared with the exact
+// value of the circuit computed on the raw inputs and constants. A wire is correct if its
+// exact value is in the message space of the parameters and its decoded value is less than
+// one unit of their last place away from it, so the report shows where the truncation of
+// the inputs grows too large or where a value overflows the message space.
+// The simulation stops at the first failure, which is described in the report.
+// An error is only returned when the circuit or the number of inputs is invalid.
+func (circuit *Circuit) Simulate(inputs []float64, params *Parameters) (*SimulationReport, error) {
+	if len(inputs) != circuit.inputs {
+		return nil, ErrCircuitInputsAreDifferent
+	}
+	err := circuit.validate()
+	if err != nil {
+		return nil, err
+	}
+	report := new(SimulationReport)
+	// Encode inputs.
+	for i, input := range inputs {
+		// The reference value is the exact rational of the float, nil if it is not finite.
+		expected := new(big.Rat).SetFloat64(input)
+		report.Expected = append(report.Expected, expected)
+		enc, err := NewEncoded(input, params)
+		if err != nil {
+			report.Failure = &SimulationFailure{Gate: -1, Wire: i, Expected: expected, Err: err}
+			return report, nil
+		}
+		if !report.check(-1, i, enc, params) {
+			return report, nil
+		}
+	}
+	// Evaluate gates.
+	for i, gate := range circuit.gates {
+		w := circuit.inputs + i
+		left := report.Encoded[gate.Left]
+		expected := new(big.Rat)
+		var enc *Encoded
+		switch gate.Type {
+		case GateAdd:
+			expected.Add(report.Expected[gate.Left], report.Expected[gate.Right])
+			enc, err = left.Add(report.Encoded[gate.Right])
+		case GateMul:
+			expected.Mul(report.Expected[gate.Left], report.Expected[gate.Right])
+			enc, err = left.Mul(report.Encoded[gate.Right])
+		case GateMulConstant:
+			var k *Encoded
+			k, err = NewEncoded(gate.Constant, params)
+			if c := new(big.Rat).SetFloat64(gate.Constant); c != nil {
+				expected.Mul(report.Expected[gate.Left], c)
+			} else {
+				expected = nil
+			}
+			if err == nil {
+				enc, err = left.Mul(k)
+			}
+		}
+		report.Expected = append(report.Expected, expected)
+		if err != nil {
+			report.Failure = &SimulationFailure{Gate: i, Wire: w, Expected: expected, Err: err}
+			return report, nil
+		}
+		if !report.check(i, w, enc, params) {
+			return report, nil
+		}
+	}
+	return report, nil
+}
+
+// check decodes a wire and compares it with its exact value.
+// A failure is recorded in the report if the exact value is not in the message space
+// of the parameters, or if the decoded value is one unit of their last place away or more.
+func (report *SimulationReport) check(gate, wire int, enc *Encoded, params *Parameters) bool {
+	expected := report.Expected[wire]
+	got := enc.Rat()
+	if expected == nil {
+		report.Failure = &SimulationFailure{Gate: gate, Wire: wire, Got: got, Err: ErrNumeratorIsNotInTheMessageSpaceRange}
+		return false
+	}
+	if n, ok := fractionNumerator(expected, params); !ok || inputIsInvalid(n, params) {
+		report.Failure = &SimulationFailure{Gate: gate, Wire: wire, Expected: expected, Got: got,
+			Err: ErrNumeratorIsNotInTheMessageSpaceRange}
+		return false
+	}
+	// Distance to the exact value in units of the last place: |got - expected| x D x b^(|p|).
+	diff := new(big.Rat).Sub(got, expected)
+	diff.Abs(diff).Mul(diff, new(big.Rat).SetInt(codeDenominator(params)))
+	if diff.Cmp(big.NewRat(1, 1)) >= 0 {
+		report.Failure = &SimulationFailure{Gate: gate, Wire: wire, Expected: expected, Got: got}
+		return false
+	}
+	r, err := enc.Decode()
+	if err != nil {
+		report.Failure = &SimulationFailure{Gate: gate, Wire: wire, Expected: expected, Got: got, Err: err}
+		return false
+	}
+	report.Encoded = append(report.Encoded, enc)
+	report.Decoded = append(report.Decoded, r)
+	return true
+}
@@ -0,0 +1,161 @@
+package polyrat
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestCircuitSimulate(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	// Circuit: 1.5 x (x0 + x1) x x2.
+	circuit := NewCircuit(3)
+	s := circuit.Add(0, 1)
+	m := circuit.Mul(s, 2)
+	o := circuit.MulConstant(m, 1.5)
+	report, err := circuit.Simulate([]float64{12.34, -5.67, 8.9}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Ok() {
+		t.Fatalf("expected a correct simulation but failed at gate %d: %v", report.Failure.Gate, report.Failure.Err)
+	}
+	// Check the output wire, whose exact value is computed on the raw floats.
+	er := new(big.Rat).Mul(new(big.Rat).SetFloat64(1.5), new(big.Rat).Mul(
+		new(big.Rat).Add(new(big.Rat).SetFloat64(12.34), new(big.Rat).SetFloat64(-5.67)),
+		new(big.Rat).SetFloat64(8.9)))
+	if report.Expected[o].Cmp(er) != 0 {
+		t.Errorf("expected reference %s but got %s", er.String(), report.Expected[o].String())
+	}
+	if report.Decoded[o] != 89.0445 {
+		t.Errorf("expected decoded value %f but got %f", 89.0445, report.Decoded[o])
+	}
+	if report.Encoded[o].Scale() != 6 {
+		t.Errorf("expected scale %d but got %d", 6, report.Encoded[o].Scale())
+	}
+}
+
+func TestCircuitSimulateFailure(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Circuit: (((x0 + x0) x x0) x x0) x x0.
+	circuit := NewCircuit(1)
+	s := circuit.Add(0, 0)
+	m := circuit.Mul(s, 0)
+	m = circuit.Mul(m, 0)
+	circuit.Mul(m, 0)
+	report, err := circuit.Simulate([]float64{1.5}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The third multiplication needs 13 integer and 8 fractional digits, which do not fit in d = 16.
+	if report.Ok() {
+		t.Fatal("expected a failed simulation")
+	}
+	if report.Failure.Gate != 3 || report.Failure.Wire != 4 {
+		t.Errorf("expected failure at gate %d (wire %d) but got gate %d (wire %d)", 3, 4, report.Failure.Gate, report.Failure.Wire)
+	}
+	if !errors.Is(report.Failure.Err, ErrDIsLessThanOrEqualToQPlusP) {
+		t.Errorf("expected error %v but got %v", ErrDIsLessThanOrEqualToQPlusP, report.Failure.Err)
+	}
+	if len(report.Decoded) != 4 {
+		t.Errorf("expected %d decoded wires but got %d", 4, len(report.Decoded))
+	}
+	// Check that an input outside the message space is reported.
+	report, err = circuit.Simulate([]float64{5000.0}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Ok() || report.Failure.Gate != -1 || !errors.Is(report.Failure.Err, ErrNumeratorIsNotInTheMessageSpaceRange) {
+		t.Errorf("expected failure when encoding the input")
+	}
+}
+
+func TestCircuitSimulateOverflow(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	// Circuit: ((x0 x x0) x x0) x x0, whose digits fit in d = 32 but whose values do not fit in the message space.
+	circuit := NewCircuit(1)
+	m := circuit.Mul(0, 0)
+	m = circuit.Mul(m, 0)
+	circuit.Mul(m, 0)
+	report, err := circuit.Simulate([]float64{12.0}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 12^3 = 1728 is in the message space but 12^4 = 20736 is greater than its upper bound 4444.44.
+	if report.Ok() {
+		t.Fatal("expected a failed simulation")
+	}
+	if report.Failure.Gate != 2 || report.Failure.Wire != 3 {
+		t.Errorf("expected failure at gate %d (wire %d) but got gate %d (wire %d)", 2, 3, report.Failure.Gate, report.Failure.Wire)
+	}
+	if !errors.Is(report.Failure.Err, ErrNumeratorIsNotInTheMessageSpaceRange) {
+		t.Errorf("expected error %v but got %v", ErrNumeratorIsNotInTheMessageSpaceRange, report.Failure.Err)
+	}
+	if report.Failure.Got == nil || report.Failure.Got.Cmp(big.NewRat(20736, 1)) != 0 {
+		t.Errorf("expected decoded value %d but got %v", 20736, report.Failure.Got)
+	}
+}
+
+func TestCircuitSimulatePrecision(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	// Circuit: x0 x x1.
+	circuit := NewCircuit(2)
+	circuit.Mul(0, 1)
+	// The inputs are truncated to 1.23 and 100.0, which is 0.5 away from the exact product 123.5.
+	report, err := circuit.Simulate([]float64{1.235, 100.0}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Ok() {
+		t.Fatal("expected a failed simulation")
+	}
+	if report.Failure.Gate != 0 || report.Failure.Wire != 2 {
+		t.Errorf("expected failure at gate %d (wire %d) but got gate %d (wire %d)", 0, 2, report.Failure.Gate, report.Failure.Wire)
+	}
+	if report.Failure.Err != nil {
+		t.Errorf("expected no error but got %v", report.Failure.Err)
+	}
+	if report.Failure.Got.Cmp(big.NewRat(123, 1)) != 0 {
+		t.Errorf("expected decoded value %d but got %s", 123, report.Failure.Got.String())
+	}
+	if len(report.Decoded) != 2 {
+		t.Errorf("expected %d decoded wires but got %d", 2, len(report.Decoded))
+	}
+}
+
+func TestCircuitValidate(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Check that an error is thrown when the number of inputs is different.
+	circuit := NewCircuit(2)
+	circuit.Add(0, 1)
+	_, err = circuit.Simulate([]float64{1.0}, params)
+	if !errors.Is(err, ErrCircuitInputsAreDifferent) {
+		t.Errorf("expected error %v but got %v", ErrCircuitInputsAreDifferent, err)
+	}
+	// Check that an error is thrown when a gate uses a wire that is not available.
+	circuit.Mul(0, 3)
+	_, err = circuit.Simulate([]float64{1.0, 2.0}, params)
+	if !errors.Is(err, ErrCircuitWireIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrCircuitWireIsInvalid, err)
+	}
+}
@@ -0,0 +1,443 @@
+// Command polyrat encodes rationals into polynomial codes and decodes them back.
+//
+// Usage:
+//
+//	polyrat encode [-p -4] [-q 11] [-d 16] [-format text|json] [number ...]
+//	polyrat decode [-p -4] [-q 11] [-d 16] [-format text|json] [code ...]
+//	polyrat params validate [-p -4] [-q 11] [-d 16] [-format text|json]
+//	polyrat params suggest -max 98123.45 -decimals 2 [-format text|json]
+//	polyrat inspect [-p -4] [-q 11] [-d 16] [-format text|json] [code ...]
+//	polyrat stream encode|decode [-p -4] [-q 11] [-d 16] -columns a,b [-input csv|ndjson] [-wire json|dense|sparse|packed]
+//
+// Numbers and codes are read from the arguments or, when there are none, from
+// the standard input, one per line. Codes are given as coefficients separated by
+// spaces or commas, or as the JSON objects printed by the json format, which
+// carry their own parameters. Negative numbers given as arguments should follow "--".
+//
+// The stream subcommand encodes or decodes columns of a CSV or NDJSON dataset read from
+// the standard input and writes it to the standard output. Codes are written as JSON codes
+// or as base64 wire containers. Rows that cannot be processed are reported on the standard
+// error and left out of the output.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Algemetric/polyrat"
+)
+
+// errUsage reports an invalid command line.
+var errUsage = errors.New("usage: polyrat encode|decode|params validate|params suggest|inspect|stream encode|stream decode [flags] [input ...]")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes a command and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 1 && args[0] == "stream" {
+		return stream(args[1:], stdin, stdout, stderr)
+	}
+	err := dispatch(args, stdin, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, "polyrat:", err)
+		return 1
+	}
+	return 0
+}
+
+// dispatch selects the subcommand.
+func dispatch(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "encode":
+		return encode(args[1:], stdin, stdout)
+	case "decode":
+		return decode(args[1:], stdin, stdout)
+	case "inspect":
+		return inspect(args[1:], stdin, stdout)
+	case "params":
+		if len(args) < 2 {
+			return errUsage
+		}
+		switch args[1] {
+		case "validate":
+			return validateParams(args[2:], stdout)
+		case "suggest":
+			return suggestParams(args[2:], stdout)
+		}
+	}
+	return errUsage
+}
+
+// options are the flags shared by the subcommands.
+type options struct {
+	flags  *flag.FlagSet
+	p      *int
+	q      *int
+	d      *int
+	format *string
+}
+
+// newOptions creates the flag set of a subcommand.
+// Parameter flags are only added when withParams is set.
+func newOptions(name string, withParams bool) *options {
+	opts := new(options)
+	opts.flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	opts.flags.SetOutput(io.Discard)
+	if withParams {
+		opts.p = opts.flags.Int("p", -4, "lower power")
+		opts.q = opts.flags.Int("q", 11, "higher power")
+		opts.d = opts.flags.Int("d", 16, "degree")
+	}
+	opts.format = opts.flags.String("format", "text", "output format: text or json")
+	return opts
+}
+
+// parse parses the flags and checks the output format.
+func (opts *options) parse(args []string) error {
+	err := opts.flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *opts.format != "text" && *opts.format != "json" {
+		return fmt.Errorf("invalid format %q", *opts.format)
+	}
+	return nil
+}
+
+// json checks if the output format is JSON.
+func (opts *options) json() bool {
+	return *opts.format == "json"
+}
+
+// params creates the parameters given by the flags.
+func (opts *options) params() (*polyrat.Parameters, error) {
+	return polyrat.NewParameters(*opts.p, *opts.q, *opts.d)
+}
+
+// inputs returns the arguments or, when there are none, the non-empty lines of the standard input.
+func inputs(args []string, stdin io.Reader) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	var lines []string
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseCode parses a code given as coefficients or as a JSON object.
+// JSON objects carry their own parameters, otherwise the given parameters are used.
+func parseCode(s string, params *polyrat.Parameters) (*polyrat.Code, error) {
+	if strings.HasPrefix(s, "{") {
+		code := new(polyrat.Code)
+		err := json.Unmarshal([]byte(s), code)
+		if err != nil {
+			return nil, err
+		}
+		return code, nil
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t' || r == '[' || r == ']'
+	})
+	coeffs := make([]int64, len(fields))
+	for i, f := range fields {
+		c, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coefficient %q", f)
+		}
+		coeffs[i] = c
+	}
+	return polyrat.NewCode(coeffs, params)
+}
+
+// formatCoefficients joins coefficients with spaces.
+func formatCoefficients(coeffs []int64) string {
+	s := make([]string, len(coeffs))
+	for i, c := range coeffs {
+		s[i] = strconv.FormatInt(c, 10)
+	}
+	return strings.Join(s, " ")
+}
+
+// formatValue formats a decoded rational.
+func formatValue(r float64) string {
+	return strconv.FormatFloat(r, 'f', -1, 64)
+}
+
+// encode prints the code of every number.
+func encode(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("encode", true)
+	err := opts.parse(args)
+	if err != nil {
+		return err
+	}
+	params, err := opts.params()
+	if err != nil {
+		return err
+	}
+	in, err := inputs(opts.flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	for _, s := range in {
+		r, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		code, err := polyrat.EncodeCode(r, params)
+		if err != nil {
+			return err
+		}
+		if opts.json() {
+			data, err := json.Marshal(code)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(stdout, string(data))
+		} else {
+			fmt.Fprintln(stdout, formatCoefficients(code.Coefficients))
+		}
+	}
+	return nil
+}
+
+// decode prints the rational of every code.
+func decode(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("decode", true)
+	err := opts.parse(args)
+	if err != nil {
+		return err
+	}
+	params, err := opts.params()
+	if err != nil {
+		return err
+	}
+	in, err := inputs(opts.flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	for _, s := range in {
+		code, err := parseCode(s, params)
+		if err != nil {
+			return err
+		}
+		r, err := code.Decode()
+		if err != nil {
+			return err
+		}
+		if opts.json() {
+			fmt.Fprintf(stdout, "{\"value\":%s}\n", formatValue(r))
+		} else {
+			fmt.Fprintln(stdout, formatValue(r))
+		}
+	}
+	return nil
+}
+
+// validateParams checks the parameters given by the flags.
+func validateParams(args []string, stdout io.Writer) error {
+	opts := newOptions("params validate", true)
+	err := opts.parse(args)
+	if err != nil {
+		return err
+	}
+	params, err := opts.params()
+	if opts.json() {
+		res := struct {
+			Valid bool   `json:"valid"`
+			Error string `json:"error,omitempty"`
+		}{Valid: err == nil}
+		if err != nil {
+			res.Error = err.Error()
+		}
+		data, _ := json.Marshal(res)
+		fmt.Fprintln(stdout, string(data))
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "valid: %s\n", params.String())
+	return nil
+}
+
+// suggestParams prints the smallest parameters for a range of rationals.
+func suggestParams(args []string, stdout io.Writer) error {
+	opts := newOptions("params suggest", false)
+	maxAbs := opts.flags.Float64("max", 0, "greatest absolute value to encode")
+	decimals := opts.flags.Int("decimals", 2, "number of decimal places")
+	err := opts.parse(args)
+	if err != nil {
+		return err
+	}
+	params, err := polyrat.SuggestParameters(*maxAbs, *decimals)
+	if err != nil {
+		return err
+	}
+	if opts.json() {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(data))
+	} else {
+		fmt.Fprintln(stdout, params.String())
+	}
+	return nil
+}
+
+// region is a labeled range of coefficients of a code.
+type region struct {
+	Start        int     `json:"start"`
+	End          int     `json:"end"`
+	Coefficients []int64 `json:"coefficients,omitempty"`
+	Nonzero      int     `json:"nonzero"`
+}
+
+// newRegion creates the region of coefficients from start to end, inclusive.
+// The coefficients are only kept when keep is set.
+func newRegion(code []int64, start, end int, keep bool) region {
+	r := region{Start: start, End: end}
+	for i := start; i <= end; i++ {
+		if code[i] != 0 {
+			r.Nonzero++
+		}
+	}
+	if keep {
+		r.Coefficients = code[start : end+1]
+	}
+	return r
+}
+
+// inspect prints every code with its integer, padding and fractional regions.
+func inspect(args []string, stdin io.Reader, stdout io.Writer) error {
+	opts := newOptions("inspect", true)
+	err := opts.parse(args)
+	if err != nil {
+		return err
+	}
+	params, err := opts.params()
+	if err != nil {
+		return err
+	}
+	in, err := inputs(opts.flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	for _, s := range in {
+		code, err := parseCode(s, params)
+		if err != nil {
+			return err
+		}
+		r, err := code.Decode()
+		if err != nil {
+			return err
+		}
+		c, cp := code.Coefficients, code.Params
+		d, q, p := cp.Degree(), cp.MaxPower(), cp.MinPower()
+		res := struct {
+			Parameters *polyrat.Parameters `json:"parameters"`
+			Integer    region              `json:"integer"`
+			Padding    region              `json:"padding"`
+			Fraction   region              `json:"fraction"`
+			Value      json.Number         `json:"value"`
+		}{
+			Parameters: cp,
+			Integer:    newRegion(c, 0, q, true),
+			Padding:    newRegion(c, q+1, d+p-1, false),
+			Fraction:   newRegion(c, d+p, d-1, true),
+			Value:      json.Number(formatValue(r)),
+		}
+		if opts.json() {
+			data, err := json.Marshal(res)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(stdout, string(data))
+			continue
+		}
+		fmt.Fprintf(stdout, "parameters: %s\n", cp.String())
+		fmt.Fprintf(stdout, "integer   [%d, %d]: %s\n", res.Integer.Start, res.Integer.End, formatCoefficients(res.Integer.Coefficients))
+		fmt.Fprintf(stdout, "padding   [%d, %d]: %d nonzero\n", res.Padding.Start, res.Padding.End, res.Padding.Nonzero)
+		fmt.Fprintf(stdout, "fraction  [%d, %d]: %s\n", res.Fraction.Start, res.Fraction.End, formatCoefficients(res.Fraction.Coefficients))
+		fmt.Fprintf(stdout, "value: %s\n", formatValue(r))
+	}
+	return nil
+}
+
+// stream encodes or decodes the columns of a dataset and returns the exit status.
+// The exit status is 1 if any row could not be processed.
+func stream(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := newOptions("stream", true)
+	columns := opts.flags.String("columns", "", "comma-separated columns or fields to encode or decode")
+	input := opts.flags.String("input", "csv", "record format: csv or ndjson")
+	wire := opts.flags.String("wire", "json", "code format: json, dense, sparse or packed")
+	err := opts.parse(args[1:])
+	if err != nil {
+		fmt.Fprintln(stderr, "polyrat:", err)
+		return 1
+	}
+	// Splitting an empty flag would give a single column with an empty name.
+	var so polyrat.StreamOptions
+	if *columns != "" {
+		so.Columns = strings.Split(*columns, ",")
+	}
+	switch *input {
+	case "csv":
+		so.Format = polyrat.FormatCSV
+	case "ndjson":
+		so.Format = polyrat.FormatNDJSON
+	default:
+		fmt.Fprintf(stderr, "polyrat: invalid input format %q\n", *input)
+		return 1
+	}
+	encodings := map[string]int{"dense": polyrat.EncodingDense, "sparse": polyrat.EncodingSparse, "packed": polyrat.EncodingPacked}
+	if e, ok := encodings[*wire]; ok {
+		so.Container = true
+		so.Encoding = e
+	} else if *wire != "json" {
+		fmt.Fprintf(stderr, "polyrat: invalid wire format %q\n", *wire)
+		return 1
+	}
+	var res *polyrat.StreamResult
+	switch args[0] {
+	case "encode":
+		so.Params, err = opts.params()
+		if err == nil {
+			res, err = polyrat.EncodeStream(stdin, stdout, so)
+		}
+	case "decode":
+		res, err = polyrat.DecodeStream(stdin, stdout, so)
+	default:
+		err = errUsage
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "polyrat:", err)
+		return 1
+	}
+	for _, e := range res.Errors {
+		fmt.Fprintln(stderr, e)
+	}
+	if len(res.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
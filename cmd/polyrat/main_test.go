@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Algemetric/polyrat"
+)
+
+// runCommand runs a command with the given standard input and returns its output and exit status.
+func runCommand(args []string, stdin string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), stderr.String(), status
+}
+
+func TestEncodeDecode(t *testing.T) {
+	// Encode from arguments.
+	out, _, status := runCommand([]string{"encode", "98123.45"}, "")
+	if status != 0 {
+		t.Fatalf("expected exit status 0 but got %d", status)
+	}
+	ec := "4 2 1 -2 0 1 0 0 0 0 0 0 0 0 5 5\n"
+	if out != ec {
+		t.Errorf("expected code %q but got %q", ec, out)
+	}
+	// Decode from the standard input.
+	out, _, status = runCommand([]string{"decode"}, ec)
+	if status != 0 || out != "98123.45\n" {
+		t.Errorf("expected 98123.45 but got %q (exit status %d)", out, status)
+	}
+	// JSON codes carry their own parameters.
+	out, _, _ = runCommand([]string{"encode", "-p", "-2", "-q", "3", "-d", "8", "-format", "json", "--", "-5231.87"}, "")
+	out, _, status = runCommand([]string{"decode", "-format", "json"}, out)
+	if status != 0 || out != "{\"value\":-5231.87}\n" {
+		t.Errorf("expected {\"value\":-5231.87} but got %q (exit status %d)", out, status)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	// Check that a number outside of the message space is reported.
+	_, errOut, status := runCommand([]string{"encode", "-p", "-2", "-q", "3", "-d", "16", "4551.92"}, "")
+	if status != 1 || !strings.Contains(errOut, "message space") {
+		t.Errorf("expected a message space error but got %q (exit status %d)", errOut, status)
+	}
+	if strings.Contains(errOut, "polyrat: polyrat:") {
+		t.Errorf("expected a single prefix but got %q", errOut)
+	}
+	// Check that an unknown subcommand is reported.
+	_, errOut, status = runCommand([]string{"compress"}, "")
+	if status != 1 || !strings.Contains(errOut, "usage") {
+		t.Errorf("expected a usage error but got %q (exit status %d)", errOut, status)
+	}
+}
+
+func TestParams(t *testing.T) {
+	out, _, status := runCommand([]string{"params", "validate", "-p", "-2", "-q", "3", "-d", "8"}, "")
+	if status != 0 || out != "valid: b=10, p=-2, q=3, d=8\n" {
+		t.Errorf("expected valid parameters but got %q (exit status %d)", out, status)
+	}
+	out, _, status = runCommand([]string{"params", "validate", "-d", "12", "-format", "json"}, "")
+	if status != 1 || out != "{\"valid\":false,\"error\":\"degree should be a power of 2\"}\n" {
+		t.Errorf("expected invalid parameters but got %q (exit status %d)", out, status)
+	}
+	out, _, status = runCommand([]string{"params", "suggest", "-max", "98123.45", "-decimals", "2", "-format", "json"}, "")
+	if status != 0 || out != "{\"base\":10,\"p\":-2,\"q\":5,\"d\":8}\n" {
+		t.Errorf("expected suggested parameters but got %q (exit status %d)", out, status)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	out, _, status := runCommand([]string{"inspect", "4,2,1,-2,0,1,0,0,0,0,0,0,0,0,5,5"}, "")
+	if status != 0 {
+		t.Fatalf("expected exit status 0 but got %d", status)
+	}
+	e := "parameters: b=10, p=-4, q=11, d=16\n" +
+		"integer   [0, 11]: 4 2 1 -2 0 1 0 0 0 0 0 0\n" +
+		"padding   [12, 11]: 0 nonzero\n" +
+		"fraction  [12, 15]: 0 0 5 5\n" +
+		"value: 98123.45\n"
+	if out != e {
+		t.Errorf("expected\n%s\nbut got\n%s", e, out)
+	}
+}
+
+func TestStream(t *testing.T) {
+	in := "id,x\n1,1.5\n2,99999\n3,-2.25\n"
+	out, errOut, status := runCommand([]string{"stream", "encode", "-p", "-2", "-q", "3", "-d", "8", "-columns", "x", "-wire", "sparse"}, in)
+	// Row 2 is outside of the message space.
+	if status != 1 || !strings.Contains(errOut, "row 2") {
+		t.Errorf("expected an error at row 2 but got %q (exit status %d)", errOut, status)
+	}
+	out, errOut, status = runCommand([]string{"stream", "decode", "-columns", "x"}, out)
+	if status != 0 || out != "id,x\n1,1.5\n3,-2.25\n" {
+		t.Errorf("expected decoded rows but got %q, %q (exit status %d)", out, errOut, status)
+	}
+	// Columns are required.
+	_, errOut, status = runCommand([]string{"stream", "decode"}, out)
+	if status != 1 || !strings.Contains(errOut, polyrat.ErrStreamColumnsAreEmpty.Error()) {
+		t.Errorf("expected error %q but got %q (exit status %d)", polyrat.ErrStreamColumnsAreEmpty, errOut, status)
+	}
+}
@@ -0,0 +1,40 @@
+// Command polyratd serves the polyrat encoding over HTTP with JSON endpoints.
+//
+// Usage:
+//
+//	polyratd [-addr 127.0.0.1:8080] [-max-body 1048576] [-max-batch 1024] [-max-degree 4096]
+//
+// Endpoints:
+//
+//	POST /v1/encode      {"parameters": {...}, "value": 1.5} or {"parameters": {...}, "values": [1.5, 2]}
+//	POST /v1/decode      {"code": {...}} or {"codes": [{...}, {...}]}
+//	GET  /v1/parameters  ?p=-4&q=11&d=16 to validate, or ?max=98123.45&decimals=2 to suggest
+//
+// Parameters and codes use the JSON representation of the library. Errors are
+// returned as {"error": {"code": "...", "message": "...", "index": 0}} with a 4xx status.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "listen address")
+	maxBody := flag.Int64("max-body", 1<<20, "maximum request body size in bytes")
+	maxBatch := flag.Int("max-batch", 1024, "maximum number of values or codes in a batch")
+	maxDegree := flag.Int("max-degree", 4096, "maximum degree of parameters and codes")
+	flag.Parse()
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(*maxBody, *maxBatch, *maxDegree),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+	log.Printf("polyratd listening on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
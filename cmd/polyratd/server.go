@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Algemetric/polyrat"
+)
+
+// errDegreeIsTooLarge is returned when a degree exceeds the maximum degree of the server.
+var errDegreeIsTooLarge = errors.New("degree exceeds the maximum degree of the server")
+
+// errorCodes maps the library errors to the codes of the error responses.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{polyrat.ErrDIsLessThanOrEqualToQPlusP, "degree_too_small"},
+	{polyrat.ErrDIsLessThanOne, "degree_less_than_one"},
+	{polyrat.ErrDIsNotAPowerOfTwo, "degree_not_power_of_two"},
+	{polyrat.ErrPIsLessThanQ, "lower_power_not_less_than_higher_power"},
+	{polyrat.ErrPIsGreaterThanOrEqualToZero, "lower_power_not_negative"},
+	{polyrat.ErrQIsLessThanOrEqualToZero, "higher_power_not_positive"},
+	{polyrat.ErrBaseIsNotSupported, "base_not_supported"},
+	{polyrat.ErrDigitSetIsInvalid, "digit_set_invalid"},
+	{polyrat.ErrCommonDenominatorIsLessThanOne, "denominator_less_than_one"},
+	{polyrat.ErrNumeratorIsNotInTheMessageSpaceRange, "out_of_message_space"},
+	{polyrat.ErrCodeDegreeIsNotAPowerOfTwo, "code_degree_not_power_of_two"},
+	{polyrat.ErrCodeDegreeIsDifferentFromDegree, "code_degree_mismatch"},
+	{polyrat.ErrCodeIndexIsInvalid, "code_index_invalid"},
+	{polyrat.ErrParametersAreMissing, "parameters_missing"},
+	{polyrat.ErrDIsGreaterThanMaxDegree, "degree_too_large"},
+	{errDegreeIsTooLarge, "degree_too_large"},
+}
+
+// errorBody is the body of an error response.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Index   *int   `json:"index,omitempty"`
+}
+
+// server handles the HTTP endpoints.
+type server struct {
+	maxBody   int64 // maxBody is the maximum request body size in bytes.
+	maxBatch  int   // maxBatch is the maximum number of values or codes in a batch.
+	maxDegree int   // maxDegree is the maximum degree of parameters and codes.
+}
+
+// newServer creates the handler of the HTTP endpoints.
+func newServer(maxBody int64, maxBatch, maxDegree int) http.Handler {
+	s := &server{maxBody: maxBody, maxBatch: maxBatch, maxDegree: maxDegree}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/encode", s.encode)
+	mux.HandleFunc("/v1/decode", s.decode)
+	mux.HandleFunc("/v1/parameters", s.parameters)
+	return mux
+}
+
+// encodeRequest is the body of an encoding request, with a single value or a batch.
+type encodeRequest struct {
+	Parameters *polyrat.Parameters `json:"parameters"`
+	Value      *float64            `json:"value"`
+	Values     []float64           `json:"values"`
+}
+
+// encodeResponse is the body of an encoding response.
+type encodeResponse struct {
+	Code  *polyrat.Code   `json:"code,omitempty"`
+	Codes []*polyrat.Code `json:"codes,omitempty"`
+}
+
+// decodeRequest is the body of a decoding request, with a single code or a batch.
+// Codes are kept raw until the degree of their parameters is checked.
+type decodeRequest struct {
+	Code  *json.RawMessage   `json:"code"`
+	Codes []*json.RawMessage `json:"codes"`
+}
+
+// decodeResponse is the body of a decoding response.
+type decodeResponse struct {
+	Value  *float64  `json:"value,omitempty"`
+	Values []float64 `json:"values,omitempty"`
+}
+
+// encode handles POST /v1/encode.
+func (s *server) encode(w http.ResponseWriter, r *http.Request) {
+	var req encodeRequest
+	if !s.readRequest(w, r, &req) {
+		return
+	}
+	if req.Parameters == nil {
+		writeError(w, http.StatusUnprocessableEntity, polyrat.ErrParametersAreMissing, nil)
+		return
+	}
+	if err := s.checkDegree(req.Parameters); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err, nil)
+		return
+	}
+	if (req.Value == nil) == (req.Values == nil) {
+		writeErrorCode(w, http.StatusBadRequest, "invalid_request", "either value or values should be given", nil)
+		return
+	}
+	if len(req.Values) > s.maxBatch {
+		writeErrorCode(w, http.StatusRequestEntityTooLarge, "batch_too_large", "batch exceeds "+strconv.Itoa(s.maxBatch)+" values", nil)
+		return
+	}
+	if req.Value != nil {
+		code, err := polyrat.EncodeCode(*req.Value, req.Parameters)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err, nil)
+			return
+		}
+		writeJSON(w, http.StatusOK, encodeResponse{Code: code})
+		return
+	}
+	codes := make([]*polyrat.Code, len(req.Values))
+	for i, v := range req.Values {
+		code, err := polyrat.EncodeCode(v, req.Parameters)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err, &i)
+			return
+		}
+		codes[i] = code
+	}
+	writeJSON(w, http.StatusOK, encodeResponse{Codes: codes})
+}
+
+// decode handles POST /v1/decode.
+func (s *server) decode(w http.ResponseWriter, r *http.Request) {
+	var req decodeRequest
+	if !s.readRequest(w, r, &req) {
+		return
+	}
+	if (req.Code == nil) == (req.Codes == nil) {
+		writeErrorCode(w, http.StatusBadRequest, "invalid_request", "either code or codes should be given", nil)
+		return
+	}
+	if len(req.Codes) > s.maxBatch {
+		writeErrorCode(w, http.StatusRequestEntityTooLarge, "batch_too_large", "batch exceeds "+strconv.Itoa(s.maxBatch)+" codes", nil)
+		return
+	}
+	if req.Code != nil {
+		code, err := s.readCode(req.Code)
+		if err != nil {
+			writeError(w, errorStatus(err), err, nil)
+			return
+		}
+		v, err := code.Decode()
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err, nil)
+			return
+		}
+		writeJSON(w, http.StatusOK, decodeResponse{Value: &v})
+		return
+	}
+	values := make([]float64, len(req.Codes))
+	for i, raw := range req.Codes {
+		if raw == nil {
+			writeError(w, http.StatusUnprocessableEntity, polyrat.ErrParametersAreMissing, &i)
+			return
+		}
+		code, err := s.readCode(raw)
+		if err != nil {
+			writeError(w, errorStatus(err), err, &i)
+			return
+		}
+		v, err := code.Decode()
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err, &i)
+			return
+		}
+		values[i] = v
+	}
+	writeJSON(w, http.StatusOK, decodeResponse{Values: values})
+}
+
+// parameters handles GET /v1/parameters.
+func (s *server) parameters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorCode(w, http.StatusMethodNotAllowed, "method_not_allowed", "method should be GET", nil)
+		return
+	}
+	query := r.URL.Query()
+	var params *polyrat.Parameters
+	var err error
+	if query.Has("max") {
+		// Suggestion.
+		maxAbs, perr := strconv.ParseFloat(query.Get("max"), 64)
+		decimals, derr := strconv.Atoi(query.Get("decimals"))
+		if perr != nil || derr != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_request", "max and decimals should be numbers", nil)
+			return
+		}
+		params, err = polyrat.SuggestParameters(maxAbs, decimals)
+	} else {
+		// Validation.
+		p, perr := strconv.Atoi(query.Get("p"))
+		q, qerr := strconv.Atoi(query.Get("q"))
+		d, derr := strconv.Atoi(query.Get("d"))
+		if perr != nil || qerr != nil || derr != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_request", "p, q and d should be integers", nil)
+			return
+		}
+		params, err = polyrat.NewParameters(p, q, d)
+	}
+	if err == nil {
+		err = s.checkDegree(params)
+	}
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err, nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, params)
+}
+
+// readRequest decodes the JSON body of a POST request within the size limit.
+// An error response is written if the request is invalid.
+func (s *server) readRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		writeErrorCode(w, http.StatusMethodNotAllowed, "method_not_allowed", "method should be POST", nil)
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBody))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil {
+		return true
+	}
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		writeErrorCode(w, http.StatusRequestEntityTooLarge, "body_too_large", err.Error(), nil)
+		return false
+	}
+	writeError(w, errorStatus(err), err, nil)
+	return false
+}
+
+// checkDegree checks that the degree of the parameters does not exceed the maximum degree.
+func (s *server) checkDegree(params *polyrat.Parameters) error {
+	if params != nil && params.Degree() > s.maxDegree {
+		return fmt.Errorf("%w (%d)", errDegreeIsTooLarge, s.maxDegree)
+	}
+	return nil
+}
+
+// readCode decodes a raw code. Its parameters are decoded and checked first,
+// so that the coefficients of a degree above the maximum are never allocated.
+func (s *server) readCode(raw *json.RawMessage) (*polyrat.Code, error) {
+	var header struct {
+		Parameters *polyrat.Parameters `json:"parameters"`
+	}
+	err := json.Unmarshal(*raw, &header)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkDegree(header.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	code := new(polyrat.Code)
+	err = json.Unmarshal(*raw, code)
+	if err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// errorStatus returns the status of a request error: errors of the library, raised
+// while decoding parameters and codes, are unprocessable and other errors are bad requests.
+func errorStatus(err error) int {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return http.StatusUnprocessableEntity
+		}
+	}
+	return http.StatusBadRequest
+}
+
+// writeError writes the error response of a library error.
+func writeError(w http.ResponseWriter, status int, err error, index *int) {
+	code := "invalid_request"
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			code = ec.code
+			break
+		}
+	}
+	writeErrorCode(w, status, code, err.Error(), index)
+}
+
+// writeErrorCode writes an error response.
+func writeErrorCode(w http.ResponseWriter, status int, code, message string, index *int) {
+	writeJSON(w, status, struct {
+		Error errorBody `json:"error"`
+	}{errorBody{Code: code, Message: message, Index: index}})
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
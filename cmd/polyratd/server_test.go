@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// request sends a request to the server and decodes the JSON response.
+func request(t *testing.T, h http.Handler, method, url, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var res map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	if err != nil {
+		t.Fatalf("invalid JSON response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, res
+}
+
+// errorCode returns the code of an error response.
+func errorCode(res map[string]interface{}) string {
+	e, _ := res["error"].(map[string]interface{})
+	c, _ := e["code"].(string)
+	return c
+}
+
+func TestServerEncodeDecode(t *testing.T) {
+	h := newServer(1<<20, 4, 64)
+	params := `{"base":10,"p":-4,"q":11,"d":16}`
+	// Single value.
+	status, res := request(t, h, http.MethodPost, "/v1/encode", `{"parameters":`+params+`,"value":98123.45}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d but got %d: %v", http.StatusOK, status, res)
+	}
+	code, _ := json.Marshal(res["code"])
+	status, res = request(t, h, http.MethodPost, "/v1/decode", `{"code":`+string(code)+`}`)
+	if status != http.StatusOK || res["value"] != 98123.45 {
+		t.Errorf("expected value 98123.45 but got %v (status %d)", res, status)
+	}
+	// Batch.
+	status, res = request(t, h, http.MethodPost, "/v1/encode", `{"parameters":`+params+`,"values":[1.5,-2.25]}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d but got %d: %v", http.StatusOK, status, res)
+	}
+	codes, _ := json.Marshal(res["codes"])
+	status, res = request(t, h, http.MethodPost, "/v1/decode", `{"codes":`+string(codes)+`}`)
+	values, _ := res["values"].([]interface{})
+	if status != http.StatusOK || len(values) != 2 || values[0] != 1.5 || values[1] != -2.25 {
+		t.Errorf("expected values [1.5 -2.25] but got %v (status %d)", res, status)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	h := newServer(256, 2, 4096)
+	params := `{"base":10,"p":-2,"q":3,"d":16}`
+	// Numerator outside of the message space, with the index of the value.
+	status, res := request(t, h, http.MethodPost, "/v1/encode", `{"parameters":`+params+`,"values":[1,4551.92]}`)
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "out_of_message_space" {
+		t.Errorf("expected out_of_message_space but got %v (status %d)", res, status)
+	}
+	if e := res["error"].(map[string]interface{}); e["index"] != 1.0 {
+		t.Errorf("expected index 1 but got %v", e["index"])
+	}
+	// Invalid parameters.
+	status, res = request(t, h, http.MethodPost, "/v1/encode", `{"parameters":{"base":10,"p":-2,"q":3,"d":12},"value":1}`)
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "degree_not_power_of_two" {
+		t.Errorf("expected degree_not_power_of_two but got %v (status %d)", res, status)
+	}
+	// Batch limit.
+	status, res = request(t, h, http.MethodPost, "/v1/encode", `{"parameters":`+params+`,"values":[1,2,3]}`)
+	if status != http.StatusRequestEntityTooLarge || errorCode(res) != "batch_too_large" {
+		t.Errorf("expected batch_too_large but got %v (status %d)", res, status)
+	}
+	// Body limit.
+	status, res = request(t, h, http.MethodPost, "/v1/encode", `{"parameters":`+params+`,"values":[`+strings.Repeat("1,", 200)+`1]}`)
+	if status != http.StatusRequestEntityTooLarge || errorCode(res) != "body_too_large" {
+		t.Errorf("expected body_too_large but got %v (status %d)", res, status)
+	}
+	// Malformed JSON.
+	status, res = request(t, h, http.MethodPost, "/v1/decode", `{"code":`)
+	if status != http.StatusBadRequest || errorCode(res) != "invalid_request" {
+		t.Errorf("expected invalid_request but got %v (status %d)", res, status)
+	}
+	// Wrong method.
+	status, _ = request(t, h, http.MethodGet, "/v1/encode", "")
+	if status != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d but got %d", http.StatusMethodNotAllowed, status)
+	}
+}
+
+func TestServerParameters(t *testing.T) {
+	h := newServer(1<<20, 4, 64)
+	status, res := request(t, h, http.MethodGet, "/v1/parameters?p=-4&q=11&d=16", "")
+	if status != http.StatusOK || res["d"] != 16.0 {
+		t.Errorf("expected valid parameters but got %v (status %d)", res, status)
+	}
+	status, res = request(t, h, http.MethodGet, "/v1/parameters?max=98123.45&decimals=2", "")
+	if status != http.StatusOK || res["q"] != 5.0 || res["d"] != 8.0 {
+		t.Errorf("expected suggested parameters but got %v (status %d)", res, status)
+	}
+	status, res = request(t, h, http.MethodGet, "/v1/parameters?p=1&q=1&d=16", "")
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "lower_power_not_less_than_higher_power" {
+		t.Errorf("expected lower_power_not_less_than_higher_power but got %v (status %d)", res, status)
+	}
+	// Parameters given in a request body are validated with their base and digit set.
+	status, res = request(t, h, http.MethodPost, "/v1/encode", `{"parameters":{"base":10,"p":-4,"q":11,"d":16,"digits":"binary"},"value":1}`)
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "digit_set_invalid" {
+		t.Errorf("expected digit_set_invalid but got %v (status %d)", res, status)
+	}
+	status, res = request(t, h, http.MethodPost, "/v1/encode", `{"parameters":{"base":10,"p":-4,"q":11,"d":16,"digits":"naf"},"value":1}`)
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "base_not_supported" {
+		t.Errorf("expected base_not_supported but got %v (status %d)", res, status)
+	}
+	status, res = request(t, h, http.MethodPost, "/v1/encode", `{"parameters":{"base":10,"p":-4,"q":11,"d":16,"denominator":-3},"value":1}`)
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "denominator_less_than_one" {
+		t.Errorf("expected denominator_less_than_one but got %v (status %d)", res, status)
+	}
+}
+
+func TestServerDegreeLimit(t *testing.T) {
+	h := newServer(1<<20, 4, 64)
+	// Encoding with a degree above the limit of the server.
+	status, res := request(t, h, http.MethodPost, "/v1/encode", `{"parameters":{"base":10,"p":-4,"q":11,"d":128},"value":1}`)
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "degree_too_large" {
+		t.Errorf("expected degree_too_large but got %v (status %d)", res, status)
+	}
+	// Encoding with a huge degree.
+	status, res = request(t, h, http.MethodPost, "/v1/encode", `{"parameters":{"base":10,"p":-4,"q":11,"d":1099511627776},"value":1}`)
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "degree_too_large" {
+		t.Errorf("expected degree_too_large but got %v (status %d)", res, status)
+	}
+	// Decoding a code with a degree above the limit of the server, with the index of the code.
+	code := `{"parameters":{"base":10,"p":-4,"q":11,"d":16},"coefficients":[[0,1]]}`
+	huge := `{"parameters":{"base":10,"p":-4,"q":11,"d":65536},"coefficients":[[0,1]]}`
+	status, res = request(t, h, http.MethodPost, "/v1/decode", `{"codes":[`+code+`,`+huge+`]}`)
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "degree_too_large" {
+		t.Errorf("expected degree_too_large but got %v (status %d)", res, status)
+	}
+	if e := res["error"].(map[string]interface{}); e["index"] != 1.0 {
+		t.Errorf("expected index 1 but got %v", e["index"])
+	}
+	// Validating parameters with a degree above the limit of the server.
+	status, res = request(t, h, http.MethodGet, "/v1/parameters?p=-4&q=11&d=128", "")
+	if status != http.StatusUnprocessableEntity || errorCode(res) != "degree_too_large" {
+		t.Errorf("expected degree_too_large but got %v (status %d)", res, status)
+	}
+}
@@ -0,0 +1,138 @@
+package polyrat
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Code is a code paired with the parameters used to generate it.
+type Code struct {
+	Params       *Parameters // Params are the parameters used to generate the code.
+	Coefficients []int64     // Coefficients are the coefficients of the code.
+}
+
+// codeJSON is the JSON representation of a code.
+// Only the nonzero coefficients are listed, as [index, coefficient] pairs.
+type codeJSON struct {
+	Params       *Parameters `json:"parameters"`
+	Coefficients [][2]int64  `json:"coefficients"`
+}
+
+// NewCode pairs a code with the parameters used to generate it.
+func NewCode(coeffs []int64, params *Parameters) (*Code, error) {
+	// Validate degree of code.
+	err := validateDegreeOfCode(coeffs, params)
+	if err != nil {
+		return nil, err
+	}
+	code := new(Code)
+	code.Params = params
+	code.Coefficients = coeffs
+	return code, nil
+}
+
+// EncodeCode encodes a rational number into a code paired with its parameters.
+func EncodeCode(rat float64, params *Parameters) (*Code, error) {
+	coeffs, err := Encode(rat, params)
+	if err != nil {
+		return nil, err
+	}
+	return NewCode(coeffs, params)
+}
+
+// Decode decodes the code into its original rational.
+func (code *Code) Decode() (float64, error) {
+	return Decode(code.Coefficients, code.Params)
+}
+
+// MarshalJSON encodes the code as a JSON object with its parameters and its nonzero coefficients.
+func (code Code) MarshalJSON() ([]byte, error) {
+	cj := codeJSON{Params: code.Params, Coefficients: [][2]int64{}}
+	for i, c := range code.Coefficients {
+		if c != 0 {
+			cj.Coefficients = append(cj.Coefficients, [2]int64{int64(i), c})
+		}
+	}
+	return json.Marshal(cj)
+}
+
+// UnmarshalJSON decodes a code encoded with MarshalJSON.
+// The dense coefficients are rebuilt with length d and validated as in Decode.
+func (code *Code) UnmarshalJSON(data []byte) error {
+	var cj codeJSON
+	err := json.Unmarshal(data, &cj)
+	if err != nil {
+		return err
+	}
+	if cj.Params == nil {
+		return ErrParametersAreMissing
+	}
+	// Indices are distinct and less than d, so there are at most d pairs.
+	d := cj.Params.Degree()
+	if len(cj.Coefficients) > d {
+		return ErrCodeIndexIsInvalid
+	}
+	coeffs := make([]int64, d)
+	seen := make(map[int64]bool)
+	for _, pair := range cj.Coefficients {
+		if pair[0] < 0 || pair[0] >= int64(d) || seen[pair[0]] {
+			return ErrCodeIndexIsInvalid
+		}
+		seen[pair[0]] = true
+		coeffs[pair[0]] = pair[1]
+	}
+	// Validate degree of code.
+	err = validateDegreeOfCode(coeffs, cj.Params)
+	if err != nil {
+		return err
+	}
+	code.Params = cj.Params
+	code.Coefficients = coeffs
+	return nil
+}
+
+// Value implements driver.Valuer with the bit-packed format of MarshalCode.
+// A code without coefficients is stored as NULL.
+func (code Code) Value() (driver.Value, error) {
+	if code.Coefficients == nil {
+		return nil, nil
+	}
+	if code.Params == nil {
+		return nil, ErrParametersAreMissing
+	}
+	return MarshalCode(code.Coefficients, code.Params)
+}
+
+// Scan implements sql.Scanner for codes stored with Value.
+// The bit-packed format does not carry the parameters, so they should be set
+// before scanning, for instance from the parameter ID stored next to the code.
+// NULL is scanned as a code without coefficients, even if the parameters are not set.
+func (code *Code) Scan(src interface{}) error {
+	if src == nil {
+		code.Coefficients = nil
+		return nil
+	}
+	if code.Params == nil {
+		return ErrParametersAreMissing
+	}
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return ErrScanTypeIsNotSupported
+	}
+	coeffs, err := UnmarshalCode(data, code.Params)
+	if err != nil {
+		return err
+	}
+	// Validate degree of code.
+	err = validateDegreeOfCode(coeffs, code.Params)
+	if err != nil {
+		return err
+	}
+	code.Coefficients = coeffs
+	return nil
+}
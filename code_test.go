@@ -0,0 +1,165 @@
+package polyrat
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCodeJSON(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-4, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	code, err := EncodeCode(98123.45, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only nonzero coefficients are listed.
+	ej := `{"parameters":{"base":10,"p":-4,"q":11,"d":16},"coefficients":[[0,4],[1,2],[2,1],[3,-2],[5,1],[14,5],[15,5]]}`
+	if string(data) != ej {
+		t.Errorf("expected JSON %s but got %s", ej, string(data))
+	}
+	// Codes held by value are encoded in the same way.
+	data, err = json.Marshal(*code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != ej {
+		t.Errorf("expected JSON %s but got %s", ej, string(data))
+	}
+	// Unmarshal rebuilds the dense code.
+	read := new(Code)
+	err = json.Unmarshal(data, read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec := []int64{4, 2, 1, -2, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 5, 5}
+	if len(read.Coefficients) != len(ec) {
+		t.Fatalf("expected code has %d elements but got %d", len(ec), len(read.Coefficients))
+	}
+	for i := 0; i < len(ec); i++ {
+		if read.Coefficients[i] != ec[i] {
+			t.Errorf("expected code value %d at position %d but got %d", ec[i], i, read.Coefficients[i])
+		}
+	}
+	dr, err := read.Decode()
+	if err != nil {
+		t.Error(err)
+	}
+	if dr != 98123.45 {
+		t.Errorf("error decoding, expected %f but got %f", 98123.45, dr)
+	}
+}
+
+func TestCodeJSONErrors(t *testing.T) {
+	// Check that an error is thrown when an index is not less than the degree.
+	err := json.Unmarshal([]byte(`{"parameters":{"base":10,"p":-4,"q":11,"d":16},"coefficients":[[16,1]]}`), new(Code))
+	if !errors.Is(err, ErrCodeIndexIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrCodeIndexIsInvalid, err)
+	}
+	// Check that an error is thrown when an index is repeated.
+	err = json.Unmarshal([]byte(`{"parameters":{"base":10,"p":-4,"q":11,"d":16},"coefficients":[[1,1],[1,2]]}`), new(Code))
+	if !errors.Is(err, ErrCodeIndexIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrCodeIndexIsInvalid, err)
+	}
+	// Check that an error is thrown when the parameters are invalid.
+	err = json.Unmarshal([]byte(`{"parameters":{"base":10,"p":-4,"q":11,"d":12},"coefficients":[]}`), new(Code))
+	if !errors.Is(err, ErrDIsNotAPowerOfTwo) {
+		t.Errorf("expected error %v but got %v", ErrDIsNotAPowerOfTwo, err)
+	}
+	// Check that an error is thrown before allocating the coefficients of a huge degree.
+	err = json.Unmarshal([]byte(`{"parameters":{"base":10,"p":-4,"q":11,"d":1099511627776},"coefficients":[]}`), new(Code))
+	if !errors.Is(err, ErrDIsGreaterThanMaxDegree) {
+		t.Errorf("expected error %v but got %v", ErrDIsGreaterThanMaxDegree, err)
+	}
+	// Check that an error is thrown when there are more pairs than coefficients.
+	err = json.Unmarshal([]byte(`{"parameters":{"base":10,"p":-1,"q":1,"d":4},"coefficients":[[0,1],[1,1],[2,1],[3,1],[0,1]]}`), new(Code))
+	if !errors.Is(err, ErrCodeIndexIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrCodeIndexIsInvalid, err)
+	}
+	// Check that an error is thrown when the parameters are missing.
+	err = json.Unmarshal([]byte(`{"coefficients":[]}`), new(Code))
+	if !errors.Is(err, ErrParametersAreMissing) {
+		t.Errorf("expected error %v but got %v", ErrParametersAreMissing, err)
+	}
+}
+
+func TestCodeValueScan(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-8, 20, 2048)
+	if err != nil {
+		t.Error(err)
+	}
+	code, err := EncodeCode(83740034.866, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The code is stored with the bit-packed format.
+	v, err := code.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, ok := v.([]byte)
+	if !ok || len(data) != PackedCodeLength(params) {
+		t.Fatalf("expected %d bytes but got %v", PackedCodeLength(params), v)
+	}
+	// Codes held by value are stored in the same way.
+	var valuer driver.Valuer = *code
+	v, err = valuer.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vd, ok := v.([]byte); !ok || string(vd) != string(data) {
+		t.Errorf("expected %v but got %v", data, v)
+	}
+	// Scan with the parameters set.
+	read := &Code{Params: params}
+	err = read.Scan(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dr, err := read.Decode()
+	if err != nil {
+		t.Error(err)
+	}
+	if dr != 83740034.866 {
+		t.Errorf("error decoding, expected %f but got %f", 83740034.866, dr)
+	}
+	// NULL.
+	err = read.Scan(nil)
+	if err != nil || read.Coefficients != nil {
+		t.Errorf("expected a code without coefficients but got %v (%v)", read.Coefficients, err)
+	}
+	v, err = read.Value()
+	if err != nil || v != nil {
+		t.Errorf("expected NULL but got %v (%v)", v, err)
+	}
+	// NULL does not need the parameters.
+	err = new(Code).Scan(nil)
+	if err != nil {
+		t.Errorf("expected no error but got %v", err)
+	}
+	// Check that an error is thrown when the parameters are not set.
+	err = new(Code).Scan(data)
+	if !errors.Is(err, ErrParametersAreMissing) {
+		t.Errorf("expected error %v but got %v", ErrParametersAreMissing, err)
+	}
+	// Check that an error is thrown when the stored code was generated with other parameters.
+	other, _ := NewParameters(-4, 11, 16)
+	err = (&Code{Params: other}).Scan(data)
+	if !errors.Is(err, ErrPackedCodeLengthIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrPackedCodeLengthIsInvalid, err)
+	}
+	// Check that an error is thrown when the type is not supported.
+	err = (&Code{Params: params}).Scan(int64(1))
+	if !errors.Is(err, ErrScanTypeIsNotSupported) {
+		t.Errorf("expected error %v but got %v", ErrScanTypeIsNotSupported, err)
+	}
+}
@@ -0,0 +1,145 @@
+package polyrat
+
+import (
+	"sort"
+	"sync"
+)
+
+// Codec is an encoding scheme of rationals into polynomial codes.
+// Implementations are registered by name, so that schemes can be selected by configuration.
+type Codec interface {
+	// Encode encodes a rational number into a code.
+	Encode(rat float64) ([]int64, error)
+	// Decode decodes a code into its rational number.
+	Decode(code []int64) (float64, error)
+	// Parameters returns the parameters of the codec.
+	Parameters() *Parameters
+	// Name returns the name under which the codec is registered.
+	Name() string
+}
+
+// CodecFactory creates a codec with the given parameters.
+type CodecFactory func(params *Parameters) (Codec, error)
+
+// Names of the built-in codecs.
+const (
+	BalancedCodecName = "balanced" // BalancedCodecName is the name of the balanced fixed-point codec of Encode and Decode.
+	StandardCodecName = "standard" // StandardCodecName is the name of the fixed-point codec with standard digits.
+	NAFCodecName      = "naf"      // NAFCodecName is the name of the fixed-point codec with the non-adjacent form of base 2.
+)
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = make(map[string]CodecFactory)
+)
+
+func init() {
+	RegisterCodec(BalancedCodecName, NewBalancedCodec)
+	RegisterCodec(StandardCodecName, NewStandardCodec)
+	RegisterCodec(NAFCodecName, NewNAFCodec)
+}
+
+// RegisterCodec makes a codec available by name.
+func RegisterCodec(name string, factory CodecFactory) error {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	if _, ok := codecs[name]; ok {
+		return ErrCodecIsAlreadyRegistered
+	}
+	codecs[name] = factory
+	return nil
+}
+
+// NewCodec creates the codec registered by name with the given parameters.
+func NewCodec(name string, params *Parameters) (Codec, error) {
+	codecsMu.RLock()
+	factory, ok := codecs[name]
+	codecsMu.RUnlock()
+	if !ok {
+		return nil, ErrCodecIsNotRegistered
+	}
+	return factory(params)
+}
+
+// Codecs returns the sorted names of the registered codecs.
+func Codecs() []string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fixedPointCodec is the fixed-point scheme of Encode and Decode, with the integer digits
+// in the lower coefficients and the negated fractional digits in the upper ones.
+// Its digit set is the one of its parameters.
+type fixedPointCodec struct {
+	name   string
+	params *Parameters
+}
+
+// NewBalancedCodec creates the balanced fixed-point codec of Encode and Decode.
+// Its digits are in [-b/2, b/2), whatever the digit set of the given parameters,
+// whose base should be Base.
+func NewBalancedCodec(params *Parameters) (Codec, error) {
+	return newDigitCodec(BalancedCodecName, params, DigitsBalanced)
+}
+
+// NewStandardCodec creates the fixed-point codec with digits in [0, b) and the sign of the rational.
+// Its digits are standard, whatever the digit set of the given parameters, whose base should be Base.
+func NewStandardCodec(params *Parameters) (Codec, error) {
+	return newDigitCodec(StandardCodecName, params, DigitsStandard)
+}
+
+// NewNAFCodec creates the fixed-point codec with the non-adjacent form of base 2.
+// The given parameters should be created with DigitsNAF, since the base cannot be changed.
+func NewNAFCodec(params *Parameters) (Codec, error) {
+	return newDigitCodec(NAFCodecName, params, DigitsNAF)
+}
+
+// newDigitCodec creates a fixed-point codec with the given digit set.
+// Digit sets of the same base are interchangeable, but a rational cannot be
+// converted to another base, so an error is returned if the bases differ.
+func newDigitCodec(name string, params *Parameters, digits int) (Codec, error) {
+	if params == nil {
+		return nil, ErrParametersAreMissing
+	}
+	if params.Base() != baseOfDigitSet(digits) {
+		return nil, ErrBaseIsNotSupported
+	}
+	if params.DigitSet() != digits {
+		p, err := NewParametersWithDigits(params.MinPower(), params.MaxPower(), params.Degree(), digits)
+		if err != nil {
+			return nil, err
+		}
+		// The common denominator is kept.
+		params, err = p.WithDenominator(params.Denominator())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &fixedPointCodec{name: name, params: params}, nil
+}
+
+// Encode encodes a rational number with Encode.
+func (codec *fixedPointCodec) Encode(rat float64) ([]int64, error) {
+	return Encode(rat, codec.params)
+}
+
+// Decode decodes a code with Decode.
+func (codec *fixedPointCodec) Decode(code []int64) (float64, error) {
+	return Decode(code, codec.params)
+}
+
+// Getter for parameters.
+func (codec *fixedPointCodec) Parameters() *Parameters {
+	return codec.params
+}
+
+// Name returns the name under which the codec is registered.
+func (codec *fixedPointCodec) Name() string {
+	return codec.name
+}
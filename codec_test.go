@@ -0,0 +1,137 @@
+package polyrat
+
+import (
+	"errors"
+	"testing"
+)
+
+// codecRationals are rationals used to test every codec.
+var codecRationals = []float64{0, 1, -1, 0.01, -0.01, 123.45, -123.45, 9876.5, -5555.55, 4444.25}
+
+// nafCodecRationals are rationals used to test the codec of the non-adjacent form,
+// which only encodes multiples of powers of 1/2 exactly.
+var nafCodecRationals = []float64{0, 1, -1, 0.25, -0.25, 123.5, -123.5, 9876.5, -5555.75, 4444.25}
+
+// testCodec checks that a codec decodes the rationals it encodes.
+func testCodec(t *testing.T, codec Codec, rationals []float64) {
+	params := codec.Parameters()
+	for _, r := range rationals {
+		c, err := codec.Encode(r)
+		if err != nil {
+			t.Errorf("%s: %v", codec.Name(), err)
+			continue
+		}
+		if len(c) != params.Degree() {
+			t.Errorf("%s: expected code of degree %d but got %d", codec.Name(), params.Degree(), len(c))
+			continue
+		}
+		dr, err := codec.Decode(c)
+		if err != nil {
+			t.Errorf("%s: %v", codec.Name(), err)
+			continue
+		}
+		if dr != r {
+			t.Errorf("%s: expected %f but got %f", codec.Name(), r, dr)
+		}
+	}
+	// Codes with a different degree are rejected.
+	_, err := codec.Decode(make([]int64, 2*params.Degree()))
+	if !errors.Is(err, ErrCodeDegreeIsDifferentFromDegree) {
+		t.Errorf("%s: expected error %v but got %v", codec.Name(), ErrCodeDegreeIsDifferentFromDegree, err)
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 4, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// The non-adjacent form needs parameters of base 2.
+	nafParams, err := NewParametersWithDigits(-2, 14, 32, DigitsNAF)
+	if err != nil {
+		t.Error(err)
+	}
+	// Every registered codec is tested.
+	for _, name := range Codecs() {
+		p, rationals := params, codecRationals
+		if name == NAFCodecName {
+			p, rationals = nafParams, nafCodecRationals
+		}
+		codec, err := NewCodec(name, p)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if codec.Name() != name {
+			t.Errorf("expected codec %s but got %s", name, codec.Name())
+		}
+		testCodec(t, codec, rationals)
+	}
+}
+
+func TestDigitCodecBase(t *testing.T) {
+	params, err := NewParameters(-2, 4, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nafParams, err := NewParametersWithDigits(-2, 4, 16, DigitsNAF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Check that parameters of another base are rejected instead of converted.
+	_, err = NewNAFCodec(params)
+	if !errors.Is(err, ErrBaseIsNotSupported) {
+		t.Errorf("expected error %v but got %v", ErrBaseIsNotSupported, err)
+	}
+	_, err = NewBalancedCodec(nafParams)
+	if !errors.Is(err, ErrBaseIsNotSupported) {
+		t.Errorf("expected error %v but got %v", ErrBaseIsNotSupported, err)
+	}
+	_, err = NewStandardCodec(nafParams)
+	if !errors.Is(err, ErrBaseIsNotSupported) {
+		t.Errorf("expected error %v but got %v", ErrBaseIsNotSupported, err)
+	}
+}
+
+func TestDigitCodecDenominator(t *testing.T) {
+	// Create parameters (p, q, d) with a common denominator and standard digits.
+	params, err := NewParametersWithDigits(-2, 4, 16, DigitsStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err = params.WithDenominator(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The balanced codec changes the digit set and keeps the common denominator.
+	codec, err := NewBalancedCodec(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp := codec.Parameters()
+	if cp.DigitSet() != DigitsBalanced || cp.Denominator() != 3 {
+		t.Errorf("expected balanced digits and denominator %d but got (%s)", 3, cp.String())
+	}
+	c, err := codec.Encode(2.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dr, err := Decode(c, params)
+	if err != nil || dr != 2.5 {
+		t.Errorf("expected %f but got %f (%v)", 2.5, dr, err)
+	}
+}
+
+func TestRegisterCodec(t *testing.T) {
+	// Check that a name cannot be registered twice.
+	err := RegisterCodec(BalancedCodecName, NewBalancedCodec)
+	if !errors.Is(err, ErrCodecIsAlreadyRegistered) {
+		t.Errorf("expected error %v but got %v", ErrCodecIsAlreadyRegistered, err)
+	}
+	// Check that an error is thrown when the codec is not registered.
+	_, err = NewCodec("unknown", nil)
+	if !errors.Is(err, ErrCodecIsNotRegistered) {
+		t.Errorf("expected error %v but got %v", ErrCodecIsNotRegistered, err)
+	}
+}
@@ -0,0 +1,66 @@
+package polyrat
+
+// In Z[X]/(X^d + 1), the monomial X^(d/2) squares to -1, so it plays the role of the imaginary unit.
+// A complex number is encoded as the code of its real part plus X^(d/2) times the code of its
+// imaginary part. The imaginary digits then occupy the powers from d/2 + p to d/2 + q, which do
+// not overlap the real digits when q + |p| < d/2.
+
+// validateComplexParameters checks that the real and imaginary digits fit in the degree.
+func validateComplexParameters(params *Parameters) error {
+	if params.MaxPower()-params.MinPower() >= params.Degree()/2 {
+		return ErrComplexDoesNotFitInHalfTheDegree
+	}
+	return nil
+}
+
+// EncodeComplex encodes a complex number into a set of polynomial degrees.
+// Each part is encoded as in Encode, and the imaginary part is multiplied by X^(d/2).
+// Parameter errors are returned directly, as in NewParameters, and encoding errors as in Encode.
+func EncodeComplex(c complex128, params *Parameters) ([]int64, error) {
+	err := validateComplexParameters(params)
+	if err != nil {
+		return nil, err
+	}
+	re, err := NewEncoded(real(c), params)
+	if err != nil {
+		return nil, err
+	}
+	im, err := NewEncoded(imag(c), params)
+	if err != nil {
+		return nil, err
+	}
+	// Imaginary unit: X^(d/2).
+	shifted, err := im.poly.MulMonomial(params.Degree() / 2)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := re.poly.Add(shifted)
+	if err != nil {
+		return nil, err
+	}
+	return sum.c, nil
+}
+
+// DecodeComplex decodes a polynomial into its original complex number.
+// The real part is decoded as in Decode, and the imaginary part is decoded
+// after multiplying the code by X^(-d/2).
+func DecodeComplex(code []int64, params *Parameters) (complex128, error) {
+	// Validate input.
+	err := validateDecodingParameters(code, params)
+	if err == nil {
+		err = validateComplexParameters(params)
+	}
+	if err != nil {
+		return 0, newDecodeError(code, params, err)
+	}
+	poly, err := NewPolynomialFromCode(code, params)
+	if err != nil {
+		return 0, newDecodeError(code, params, err)
+	}
+	im, err := poly.MulMonomial(-params.Degree() / 2)
+	if err != nil {
+		return 0, newDecodeError(code, params, err)
+	}
+	re := fractionFloat(decodeFraction(poly.c, params), params)
+	return complex(re, fractionFloat(decodeFraction(im.c, params), params)), nil
+}
@@ -0,0 +1,102 @@
+package polyrat
+
+import (
+	"errors"
+	"math/cmplx"
+	"testing"
+)
+
+func TestEncodeComplex(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The real part is at the power 0 and the imaginary part at the power d/2.
+	c, err := EncodeComplex(complex(1, 2), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec := []int64{1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0}
+	for i := range ec {
+		if c[i] != ec[i] {
+			t.Fatalf("expected code %v but got %v", ec, c)
+		}
+	}
+	for _, z := range []complex128{0, 1i, -1i, complex(12.34, -56.78), complex(-0.01, 0.99), complex(-5555.55, 4444.25)} {
+		c, err := EncodeComplex(z, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dz, err := DecodeComplex(c, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dz != z {
+			t.Errorf("expected %v but got %v", z, dz)
+		}
+	}
+}
+
+func TestMulComplex(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The product has twice as many fractional and integer digits.
+	scaled, err := NewParameters(-4, 6, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := [][2]complex128{{complex(1, 2), complex(3, -4)}, {1i, 1i}, {complex(12.34, -5.67), complex(-8.9, 0.12)}, {complex(-0.5, 0.25), complex(99.99, -99.99)}}
+	for _, pair := range z {
+		ca, err := EncodeComplex(pair[0], params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cb, err := EncodeComplex(pair[1], params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pa, _ := NewPolynomialFromCode(ca, params)
+		pb, _ := NewPolynomialFromCode(cb, params)
+		prod, err := pa.Mul(pb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dz, err := DecodeComplex(prod.Coefficients(), scaled)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ez := pair[0] * pair[1]
+		if cmplx.Abs(dz-ez) > 1e-9 {
+			t.Errorf("expected product %v but got %v", ez, dz)
+		}
+	}
+}
+
+func TestComplexParameters(t *testing.T) {
+	// q + |p| = 8 is not less than d/2 = 8.
+	params, err := NewParameters(-3, 5, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = EncodeComplex(1i, params)
+	if !errors.Is(err, ErrComplexDoesNotFitInHalfTheDegree) {
+		t.Errorf("expected error %v but got %v", ErrComplexDoesNotFitInHalfTheDegree, err)
+	}
+	if err != ErrComplexDoesNotFitInHalfTheDegree {
+		t.Errorf("expected the error %v to be returned directly but got %v", ErrComplexDoesNotFitInHalfTheDegree, err)
+	}
+	_, err = DecodeComplex(make([]int64, 16), params)
+	if !errors.Is(err, ErrComplexDoesNotFitInHalfTheDegree) {
+		t.Errorf("expected error %v but got %v", ErrComplexDoesNotFitInHalfTheDegree, err)
+	}
+	// Codes with a different degree are rejected.
+	params, _ = NewParameters(-2, 5, 16)
+	_, err = DecodeComplex(make([]int64, 32), params)
+	if !errors.Is(err, ErrCodeDegreeIsDifferentFromDegree) {
+		t.Errorf("expected error %v but got %v", ErrCodeDegreeIsDifferentFromDegree, err)
+	}
+}
@@ -1,5 +1,6 @@
 package polyrat
 
 const (
-	Base = 10 // b >= 2.
+	Base      = 10      // b >= 2.
+	MaxDegree = 1 << 16 // d <= MaxDegree for parameters read from untrusted input (JSON and the wire format).
 )
@@ -5,12 +5,96 @@ import (
 )
 
 // Decode decodes a polynomial into its original rational.
+// The code is evaluated at the base of its parameters, so every digit set is decoded in the same way.
 func Decode(code []int64, params *Parameters) (float64, error) {
 	// Validate input.
 	err := validateDecodingParameters(code, params)
 	if err != nil {
-		return 0.0, err
+		return 0.0, newDecodeError(code, params, err)
 	}
+	// Fraction.
+	f := decodeFraction(code, params)
+	return fractionFloat(f, params), nil
+}
+
+// fractionFloat converts a decoded fraction into a float, rounded to p decimal places when it is not exact.
+func fractionFloat(f *big.Rat, params *Parameters) float64 {
+	// Calculates rational from fraction with "exact" flag.
+	r, e := f.Float64()
+	// If rational was not exact, then round it.
+	// Rationals with a common denominator are not rounded to p decimal places.
+	if !e && params.Denominator() == 1 {
+		r = round(r, params)
+	}
+	return r
+}
+
+// DecodeExact decodes a polynomial into the exact rational it represents.
+// It reverses EncodeRational, as well as Encode without the rounding to float64.
+func DecodeExact(code []int64, params *Parameters) (*big.Rat, error) {
+	// Validate input.
+	err := validateDecodingParameters(code, params)
+	if err != nil {
+		return nil, newDecodeError(code, params, err)
+	}
+	return decodeFraction(code, params), nil
+}
+
+// DecodeRational decodes a polynomial into the best rational approximation whose denominator
+// is at most maxDenominator, which recovers simple fractions such as 1/3 from their fixed-point
+// value. The approximation is found with the convergents and semiconvergents of the continued
+// fraction of the exact rational, and it is returned with the error bound |exact - approximation|.
+func DecodeRational(code []int64, params *Parameters, maxDenominator int64) (*big.Rat, *big.Rat, error) {
+	if maxDenominator < 1 {
+		return nil, nil, ErrMaxDenominatorIsLessThanOne
+	}
+	f, err := DecodeExact(code, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := bestApproximation(f, big.NewInt(maxDenominator))
+	bound := new(big.Rat).Sub(f, r)
+	return r, bound.Abs(bound), nil
+}
+
+// bestApproximation returns the closest rational to f whose denominator is at most m.
+func bestApproximation(f *big.Rat, m *big.Int) *big.Rat {
+	if f.Denom().Cmp(m) <= 0 {
+		return new(big.Rat).Set(f)
+	}
+	// Convergents p0/q0 and p1/q1 of the continued fraction of n/d.
+	p0, q0, p1, q1 := big.NewInt(0), big.NewInt(1), big.NewInt(1), big.NewInt(0)
+	n, d := new(big.Int).Set(f.Num()), new(big.Int).Set(f.Denom())
+	a, t := new(big.Int), new(big.Int)
+	for d.Sign() != 0 {
+		// The denominator is positive, so the Euclidean division is the floor division.
+		a.DivMod(n, d, t)
+		q2 := new(big.Int).Mul(a, q1)
+		q2.Add(q2, q0)
+		if q2.Cmp(m) > 0 {
+			break
+		}
+		p2 := new(big.Int).Mul(a, p1)
+		p2.Add(p2, p0)
+		p0, q0, p1, q1 = p1, q1, p2, q2
+		n, d = d, new(big.Int).Set(t)
+	}
+	// The semiconvergent with the greatest denominator at most m, or the last convergent.
+	k := new(big.Int).Sub(m, q0)
+	k.Div(k, q1)
+	semi := new(big.Rat).SetFrac(new(big.Int).Add(p0, new(big.Int).Mul(k, p1)), new(big.Int).Add(q0, new(big.Int).Mul(k, q1)))
+	last := new(big.Rat).SetFrac(p1, q1)
+	ds := new(big.Rat).Sub(f, semi)
+	dl := new(big.Rat).Sub(f, last)
+	if ds.Abs(ds).Cmp(dl.Abs(dl)) < 0 {
+		return semi
+	}
+	return last
+}
+
+// decodeFraction calculates the exact rational represented by a code,
+// divided by the common denominator of the parameters.
+func decodeFraction(code []int64, params *Parameters) *big.Rat {
 	// Code length.
 	l := len(code)
 	var original []int64
@@ -23,15 +107,8 @@ func Decode(code []int64, params *Parameters) (float64, error) {
 	}
 	// Decoding powers used for evaluation.
 	ep := evaluationPowers(params)
-	// Fraction.
 	f := dotProduct(ep, original)
-	// Calculates rational from fraction with "exact" flag.
-	r, e := f.Float64()
-	// If rational was not exact, then round it.
-	if !e {
-		r = roundUp(r, params)
-	}
-	return r, nil
+	return f.Quo(f, big.NewRat(params.Denominator(), 1))
 }
 
 func evaluationPowers(params *Parameters) []*big.Rat {
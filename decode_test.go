@@ -1,6 +1,8 @@
 package polyrat
 
 import (
+	"errors"
+	"math/big"
 	"strings"
 	"testing"
 )
@@ -79,6 +81,53 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+// TestDecodeNegative tests the decoding of negative rationals.
+func TestDecodeNegative(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	r := []float64{-0.01, -1.0, -67.89, -5231.87, -98123.45}
+	for i := 0; i < len(r); i++ {
+		// Encode.
+		c, err := Encode(r[i], params)
+		if err != nil {
+			t.Error(err)
+			break
+		}
+		// Decode.
+		dr, err := Decode(c, params)
+		if err != nil {
+			t.Error(err)
+			break
+		}
+		if dr != r[i] {
+			t.Errorf("error decoding, expected %f but got %f", r[i], dr)
+		}
+	}
+}
+
+// TestDecodeInexact tests the decoding of rationals that have no exact float64 representation.
+func TestDecodeInexact(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Numerators of -1310.6 and 1310.6.
+	for _, n := range []int64{-131060, 131060} {
+		c := generateCode(expansion(n, params), params)
+		dr, err := Decode(c, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if er := float64(n) / 100; dr != er {
+			t.Errorf("expected %f but got %f", er, dr)
+		}
+	}
+}
+
 func TestEvaluationPowers(t *testing.T) {
 	// Create parameters (b, p, q, d).
 	params, err := NewParameters(-4, 11, 16)
@@ -95,3 +144,111 @@ func TestEvaluationPowers(t *testing.T) {
 		}
 	}
 }
+
+// TestDecodeDigitSets tests that every digit set decodes the rationals they encode.
+// Exact fractions are used, so that the truncation of float64 rationals has no effect.
+func TestDecodeDigitSets(t *testing.T) {
+	for _, digits := range []int{DigitsBalanced, DigitsStandard, DigitsNAF} {
+		// Create parameters (p, q, d).
+		params, err := NewParametersWithDigits(-2, 3, 8, digits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Denominator b^|p|.
+		den := int64(params.Base() * params.Base())
+		lb, ub := messageSpaceBounds(params)
+		for n := int64(lb); n <= int64(ub); n += int64(ub-lb)/1000 + 1 {
+			f := big.NewRat(n, den)
+			c, err := encodeFraction(f, params)
+			if err != nil {
+				t.Fatalf("%s: %v", params.String(), err)
+			}
+			df := decodeFraction(c, params)
+			if df.Cmp(f) != 0 {
+				t.Fatalf("%s: expected %s but got %s", params.String(), f.RatString(), df.RatString())
+			}
+		}
+		// The bounds are the limits of the message space.
+		_, err = encodeFraction(big.NewRat(int64(ub)+1, den), params)
+		if !errors.Is(err, ErrNumeratorIsNotInTheMessageSpaceRange) {
+			t.Errorf("%s: expected error %v but got %v", params.String(), ErrNumeratorIsNotInTheMessageSpaceRange, err)
+		}
+		var encErr *EncodeError
+		if !errors.As(err, &encErr) || encErr.Numerator.Cmp(big.NewInt(int64(ub)+1)) != 0 {
+			t.Errorf("%s: expected an encoding error with numerator %d but got %v", params.String(), int64(ub)+1, err)
+		}
+	}
+}
+
+func TestDecodeExact(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -5231.87 is exact, without the rounding of Decode.
+	c := []int64{-2, -3, -2, -5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -3, -1}
+	f, err := DecodeExact(c, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ef := big.NewRat(-523187, 100); f.Cmp(ef) != 0 {
+		t.Errorf("expected %s but got %s", ef.RatString(), f.RatString())
+	}
+	// Check that an error is thrown when the degree is different.
+	_, err = DecodeExact(c[:8], params)
+	if !errors.Is(err, ErrCodeDegreeIsDifferentFromDegree) {
+		t.Errorf("expected error %v but got %v", ErrCodeDegreeIsDifferentFromDegree, err)
+	}
+}
+
+func TestDecodeRational(t *testing.T) {
+	tests := []struct {
+		r        float64
+		p        int
+		maxDen   int64
+		expected *big.Rat
+	}{
+		{0.33, -2, 10, big.NewRat(1, 3)},
+		{-0.6667, -4, 10, big.NewRat(-2, 3)},
+		{0.142857, -6, 10, big.NewRat(1, 7)},
+		{3.1416, -4, 200, big.NewRat(355, 113)},
+		{3.1416, -4, 1000, big.NewRat(2862, 911)},
+		{3.1416, -4, 100, big.NewRat(311, 99)},
+		{12.25, -2, 100, big.NewRat(49, 4)},
+		{0.01, -2, 1, big.NewRat(0, 1)},
+	}
+	for _, test := range tests {
+		// Create parameters (p, q, d).
+		params, err := NewParameters(test.p, 3, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := Encode(test.r, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := DecodeExact(c, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, bound, err := DecodeRational(c, params, test.maxDen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(test.expected) != 0 {
+			t.Errorf("%v: expected %s but got %s", test.r, test.expected.RatString(), r.RatString())
+		}
+		// The error bound is the distance to the exact rational.
+		e := new(big.Rat).Sub(f, r)
+		if e.Abs(e).Cmp(bound) != 0 {
+			t.Errorf("%v: expected error bound %s but got %s", test.r, e.RatString(), bound.RatString())
+		}
+	}
+	// Check that an error is thrown when the maximum denominator is less than 1.
+	params, _ := NewParameters(-2, 3, 16)
+	_, _, err := DecodeRational(make([]int64, 16), params, 0)
+	if !errors.Is(err, ErrMaxDenominatorIsLessThanOne) {
+		t.Errorf("expected error %v but got %v", ErrMaxDenominatorIsLessThanOne, err)
+	}
+}
@@ -1,14 +1,17 @@
 package polyrat
 
+import (
+	"math/big"
+)
+
 // Encode encodes a rational number into a set of polynomial degrees.
 // The function accepts as input a 64-bit rational number (float64) and bounds the precision by the lower power p.
 // If a number exceeds the precision given by p, then such number will be truncaded.
 func Encode(rat float64, params *Parameters) ([]int64, error) {
-	// Transforms a rational number into an integer.
-	n := parseRational(rat, params)
-	// Input validation.
-	if inputIsInvalid(n, params) {
-		return nil, ErrNumeratorIsNotInTheMessageSpaceRange
+	// Transforms a rational number into an integer in the message space.
+	n, err := encodeNumerator(rat, params)
+	if err != nil {
+		return nil, err
 	}
 	// Calculate expansion.
 	e := expansion(n, params)
@@ -34,3 +37,40 @@ func generateCode(exp []int64, params *Parameters) []int64 {
 	// Return code.
 	return code
 }
+
+// encodeFraction encodes an exact rational number into a set of polynomial degrees.
+// As in Encode, the precision is bounded by the lower power p and exceeding digits are truncated.
+func encodeFraction(f *big.Rat, params *Parameters) ([]int64, error) {
+	// Transforms the fraction into an integer.
+	n, ok := fractionNumerator(f, params)
+	// Input validation.
+	if !ok || inputIsInvalid(n, params) {
+		rat, _ := f.Float64()
+		return nil, newEncodeError(rat, truncatedNumerator(f, params), params, ErrNumeratorIsNotInTheMessageSpaceRange)
+	}
+	// Calculate expansion and generate code.
+	e := expansion(n, params)
+	return generateCode(e, params), nil
+}
+
+// EncodeRational encodes the exact rational num/den into a set of polynomial degrees.
+// Unlike Encode, nothing is truncated: the rational should be a multiple of 1 / (D x b^|p|),
+// where D is the common denominator of the parameters, so that 1/3 is encoded with D = 3.
+func EncodeRational(num, den int64, params *Parameters) ([]int64, error) {
+	if den == 0 {
+		return nil, ErrDenominatorIsZero
+	}
+	f := big.NewRat(num, den)
+	// Numerator of the code: f x D x b^|p|.
+	n := new(big.Rat).Mul(f, new(big.Rat).SetInt(codeDenominator(params)))
+	rat, _ := f.Float64()
+	if !n.IsInt() {
+		return nil, newEncodeError(rat, truncatedNumerator(f, params), params, ErrDenominatorIsNotExact)
+	}
+	if !n.Num().IsInt64() || numeratorIsInvalid(n.Num(), params) {
+		return nil, newEncodeError(rat, n.Num(), params, ErrNumeratorIsNotInTheMessageSpaceRange)
+	}
+	// Calculate expansion and generate code.
+	e := expansion(n.Num().Int64(), params)
+	return generateCode(e, params), nil
+}
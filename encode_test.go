@@ -1,6 +1,8 @@
 package polyrat
 
 import (
+	"errors"
+	"math/big"
 	"testing"
 )
 
@@ -51,3 +53,143 @@ func TestEncode(t *testing.T) {
 		t.Errorf("given rational should raise an error")
 	}
 }
+
+// TestEncodeStandard tests the encoding with standard digits.
+func TestEncodeStandard(t *testing.T) {
+	// Create parameters (p, q, d) with standard digits.
+	params, err := NewParametersWithDigits(-2, 5, 16, DigitsStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		r  float64
+		ec []int64
+	}{
+		{98123.45, []int64{3, 2, 1, 8, 9, 0, 0, 0, 0, 0, 0, 0, 0, 0, -5, -4}},
+		{-98123.45, []int64{-3, -2, -1, -8, -9, 0, 0, 0, 0, 0, 0, 0, 0, 0, 5, 4}},
+		{999999.99, []int64{9, 9, 9, 9, 9, 9, 0, 0, 0, 0, 0, 0, 0, 0, -9, -9}},
+	}
+	for _, test := range tests {
+		c, err := Encode(test.r, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range test.ec {
+			if test.ec[i] != c[i] {
+				t.Errorf("%v: expected code %v but got %v", test.r, test.ec, c)
+				break
+			}
+		}
+	}
+}
+
+// TestEncodeNAF tests the encoding in the non-adjacent form of base 2.
+func TestEncodeNAF(t *testing.T) {
+	// Create parameters (p, q, d) with the non-adjacent form.
+	params, err := NewParametersWithDigits(-2, 5, 16, DigitsNAF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		r  float64
+		ec []int64
+	}{
+		// 5.25 x 2^2 = 21 = 10101 in base 2.
+		{5.25, []int64{1, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1, 0}},
+		// -3.75 x 2^2 = -15 = 1 - 2^4.
+		{-3.75, []int64{0, 0, -1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1, 0}},
+	}
+	for _, test := range tests {
+		c, err := Encode(test.r, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range test.ec {
+			if test.ec[i] != c[i] {
+				t.Errorf("%v: expected code %v but got %v", test.r, test.ec, c)
+				break
+			}
+		}
+		dr, err := Decode(c, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dr != test.r {
+			t.Errorf("expected %f but got %f", test.r, dr)
+		}
+	}
+}
+
+// TestEncodeRational tests the exact encoding of rationals with a common denominator.
+func TestEncodeRational(t *testing.T) {
+	// Create parameters (p, q, d) with the common denominator 3.
+	params, err := NewParameters(-2, 4, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params3, err := params.WithDenominator(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		num, den int64
+		params   *Parameters
+	}{
+		{1, 4, params},
+		{-123, 100, params},
+		{1, 3, params3},
+		{-7, 6, params3},
+		{2, -3, params3},
+		{100, 3, params3},
+	}
+	for _, test := range tests {
+		c, err := EncodeRational(test.num, test.den, test.params)
+		if err != nil {
+			t.Fatalf("%d/%d: %v", test.num, test.den, err)
+		}
+		f, err := DecodeExact(c, test.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ef := big.NewRat(test.num, test.den); f.Cmp(ef) != 0 {
+			t.Errorf("expected %s but got %s", ef.RatString(), f.RatString())
+		}
+	}
+	// 1/3 x 3 x 10^2 = 100.
+	c, err := EncodeRational(1, 3, params3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec := []int64{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	for i := range ec {
+		if c[i] != ec[i] {
+			t.Fatalf("expected code %v but got %v", ec, c)
+		}
+	}
+	// Encode and Decode use the common denominator too.
+	dr, err := Decode(c, params3)
+	if err != nil || dr != 1.0/3 {
+		t.Errorf("expected %v but got %v (%v)", 1.0/3, dr, err)
+	}
+	c, err = Encode(1.0/3, params3)
+	if err != nil || c[0] != 1 {
+		t.Errorf("expected code %v but got %v (%v)", ec, c, err)
+	}
+	// Errors.
+	errs := []struct {
+		num, den int64
+		params   *Parameters
+		err      error
+	}{
+		{1, 0, params, ErrDenominatorIsZero},
+		{1, 3, params, ErrDenominatorIsNotExact},
+		{1, 7, params3, ErrDenominatorIsNotExact},
+		{100000, 1, params3, ErrNumeratorIsNotInTheMessageSpaceRange},
+	}
+	for _, test := range errs {
+		_, err := EncodeRational(test.num, test.den, test.params)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%d/%d: expected error %v but got %v", test.num, test.den, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package polyrat
+
+import (
+	"math/big"
+)
+
+// Encoded is a code paired with the parameters that describe its current layout.
+// The scale is the number of fractional digits carried by the code, that is, the
+// absolute value of the lower power. Multiplications add the scales of the factors,
+// so long computations should be rescaled to stay decodable.
+type Encoded struct {
+	poly   *Polynomial // poly is the code as an element of Z[X]/(X^d + 1).
+	params *Parameters // params describe the powers occupied by the digits of the code.
+}
+
+// NewEncoded encodes a rational number with its scale.
+func NewEncoded(rat float64, params *Parameters) (*Encoded, error) {
+	code, err := Encode(rat, params)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncodedFromCode(code, params)
+}
+
+// NewEncodedFromCode pairs a code with the parameters used to generate it.
+func NewEncodedFromCode(code []int64, params *Parameters) (*Encoded, error) {
+	poly, err := NewPolynomialFromCode(code, params)
+	if err != nil {
+		return nil, err
+	}
+	enc := new(Encoded)
+	enc.poly = poly
+	enc.params = params
+	return enc, nil
+}
+
+// Getter for parameters.
+func (enc *Encoded) Parameters() *Parameters {
+	return enc.params
+}
+
+// Scale returns the number of fractional digits carried by the code.
+func (enc *Encoded) Scale() int {
+	return -enc.params.MinPower()
+}
+
+// Code returns a copy of the coefficients of the code.
+func (enc *Encoded) Code() []int64 {
+	return enc.poly.Coefficients()
+}
+
+// Polynomial returns the code as an element of Z[X]/(X^d + 1).
+func (enc *Encoded) Polynomial() *Polynomial {
+	return enc.poly
+}
+
+// Decode decodes the code with its current parameters.
+func (enc *Encoded) Decode() (float64, error) {
+	return Decode(enc.poly.c, enc.params)
+}
+
+// Rat returns the exact rational represented by the code.
+func (enc *Encoded) Rat() *big.Rat {
+	return decodeFraction(enc.poly.c, enc.params)
+}
+
+// Add returns the sum of two encoded values.
+// The digits of the sum occupy the powers of both terms, which should have the same
+// base, digit set and common denominator.
+func (enc *Encoded) Add(other *Encoded) (*Encoded, error) {
+	if !enc.sameDigits(other) {
+		return nil, ErrDigitSetsAreDifferent
+	}
+	if enc.params.Denominator() != other.params.Denominator() {
+		return nil, ErrDenominatorsAreDifferent
+	}
+	poly, err := enc.poly.Add(other.poly)
+	if err != nil {
+		return nil, err
+	}
+	return enc.withLayout(poly, minInt(enc.params.MinPower(), other.params.MinPower()),
+		maxInt(enc.params.MaxPower(), other.params.MaxPower()))
+}
+
+// Sub returns the difference of two encoded values.
+// The digits of the difference occupy the powers of both terms, which should have the same
+// base, digit set and common denominator.
+func (enc *Encoded) Sub(other *Encoded) (*Encoded, error) {
+	if !enc.sameDigits(other) {
+		return nil, ErrDigitSetsAreDifferent
+	}
+	if enc.params.Denominator() != other.params.Denominator() {
+		return nil, ErrDenominatorsAreDifferent
+	}
+	poly, err := enc.poly.Sub(other.poly)
+	if err != nil {
+		return nil, err
+	}
+	return enc.withLayout(poly, minInt(enc.params.MinPower(), other.params.MinPower()),
+		maxInt(enc.params.MaxPower(), other.params.MaxPower()))
+}
+
+// MulScalar returns the encoded value multiplied by an integer scalar.
+func (enc *Encoded) MulScalar(k int64) (*Encoded, error) {
+	poly, err := enc.poly.MulScalar(k)
+	if err != nil {
+		return nil, err
+	}
+	return enc.withLayout(poly, enc.params.MinPower(), enc.params.MaxPower())
+}
+
+// Mul returns the product of two encoded values.
+// The scale of the product is the sum of the scales of the factors, and an error
+// is returned if the digits of the product do not fit in the degree.
+// The factors should have the same base and digit set, and the common denominator
+// of the product is the product of the common denominators.
+func (enc *Encoded) Mul(other *Encoded) (*Encoded, error) {
+	if !enc.sameDigits(other) {
+		return nil, ErrDigitSetsAreDifferent
+	}
+	poly, err := enc.poly.Mul(other.poly)
+	if err != nil {
+		return nil, err
+	}
+	den, ok := mulInt64(enc.params.Denominator(), other.params.Denominator())
+	if !ok {
+		return nil, ErrCoefficientOverflow
+	}
+	res, err := enc.withLayout(poly, enc.params.MinPower()+other.params.MinPower(),
+		enc.params.MaxPower()+other.params.MaxPower())
+	if err != nil {
+		return nil, err
+	}
+	res.params, err = res.params.WithDenominator(den)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Rescale divides the numerator of the encoded value by b^k, reducing its scale by k.
+// The numerator is the code multiplied by X^scale, so the division is a multiplication
+// by the monomial X^(-k): the k lowest digits wrap around to the upper coefficients,
+// where they are dropped, and their value is rounded into the lowest remaining digit.
+func (enc *Encoded) Rescale(k int) (*Encoded, error) {
+	s := enc.Scale()
+	// At least one fractional digit is kept.
+	if k < 0 || k >= s {
+		return nil, ErrRescaleIsNotLessThanScale
+	}
+	d := enc.poly.d
+	// Numerator of the encoded value.
+	num, err := enc.poly.MulMonomial(s)
+	if err != nil {
+		return nil, err
+	}
+	// Division by the monomial.
+	num, err = num.MulMonomial(-k)
+	if err != nil {
+		return nil, err
+	}
+	// Value of the dropped digits, which wrapped around negated: r = sum of c_j b^j, j < k.
+	r := new(big.Int)
+	b := big.NewInt(int64(enc.params.Base()))
+	for j := k - 1; j >= 0; j-- {
+		r.Mul(r, b)
+		r.Sub(r, big.NewInt(num.c[d-k+j]))
+		num.c[d-k+j] = 0
+	}
+	// Rounding of r / b^k to the nearest integer, with halves rounded up: floor((2r + b^k) / 2b^k).
+	// Digits are not normalized, so r has no relation with the sign of the numerator.
+	bk := new(big.Int).Exp(b, big.NewInt(int64(k)), nil)
+	rounded := new(big.Int).Lsh(r, 1)
+	rounded.Add(rounded, bk)
+	rounded.Div(rounded, new(big.Int).Lsh(bk, 1))
+	if !rounded.IsInt64() {
+		return nil, ErrCoefficientOverflow
+	}
+	c, ok := addInt64(num.c[0], rounded.Int64())
+	if !ok {
+		return nil, ErrCoefficientOverflow
+	}
+	num.c[0] = c
+	// Back to the layout of the code.
+	poly, err := num.MulMonomial(-(s - k))
+	if err != nil {
+		return nil, err
+	}
+	return enc.withLayout(poly, enc.params.MinPower()+k, enc.params.MaxPower())
+}
+
+// sameDigits reports whether two encoded values are expanded with the same base and digit set.
+func (enc *Encoded) sameDigits(other *Encoded) bool {
+	return enc.params.Base() == other.params.Base() && enc.params.DigitSet() == other.params.DigitSet()
+}
+
+// withLayout creates an encoded value whose digits occupy the powers from p to q.
+// The digit set and the common denominator of the parameters are kept.
+func (enc *Encoded) withLayout(poly *Polynomial, p, q int) (*Encoded, error) {
+	params, err := NewParametersWithDigits(p, q, poly.d, enc.params.DigitSet())
+	if err != nil {
+		return nil, err
+	}
+	params.denominator = enc.params.denominator
+	res := new(Encoded)
+	res.poly = poly
+	res.params = params
+	return res, nil
+}
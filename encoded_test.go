@@ -0,0 +1,223 @@
+package polyrat
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestEncodedMulRescale(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 5, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	// Factors and expected products rounded to 2 decimal places, with halves rounded up.
+	r := [][3]float64{{123.45, 6.78, 836.99}, {-123.45, 6.78, -836.99}, {1.25, 0.5, 0.63}, {-1.25, 0.5, -0.62}, {0.01, 0.01, 0.0}}
+	for i := 0; i < len(r); i++ {
+		a, err := NewEncoded(r[i][0], params)
+		if err != nil {
+			t.Error(err)
+		}
+		b, err := NewEncoded(r[i][1], params)
+		if err != nil {
+			t.Error(err)
+		}
+		// The product carries 4 fractional digits.
+		prod, err := a.Mul(b)
+		if err != nil {
+			t.Error(err)
+		}
+		if prod.Scale() != 4 {
+			t.Errorf("expected scale %d but got %d", 4, prod.Scale())
+		}
+		dr, err := prod.Decode()
+		if err != nil {
+			t.Error(err)
+		}
+		if math.Abs(dr-r[i][0]*r[i][1]) > 1e-9 {
+			t.Errorf("expected product %f but got %f", r[i][0]*r[i][1], dr)
+		}
+		// Rescaling drops 2 fractional digits.
+		res, err := prod.Rescale(2)
+		if err != nil {
+			t.Error(err)
+		}
+		if res.Scale() != 2 {
+			t.Errorf("expected scale %d but got %d", 2, res.Scale())
+		}
+		dr, err = res.Decode()
+		if err != nil {
+			t.Error(err)
+		}
+		if dr != r[i][2] {
+			t.Errorf("expected rescaled product %f but got %f", r[i][2], dr)
+		}
+	}
+}
+
+func TestEncodedLongComputation(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-3, 3, 64)
+	if err != nil {
+		t.Error(err)
+	}
+	// Repeated squaring of 1.1 would need 3 x 2^4 fractional digits without rescaling.
+	x, err := NewEncoded(1.1, params)
+	if err != nil {
+		t.Error(err)
+	}
+	e := 1.1
+	for i := 0; i < 4; i++ {
+		x, err = x.Mul(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, err = x.Rescale(x.Scale() - 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e = math.Round(e*e*1000) / 1000
+	}
+	if x.Scale() != 3 {
+		t.Errorf("expected scale %d but got %d", 3, x.Scale())
+	}
+	dr, err := x.Decode()
+	if err != nil {
+		t.Error(err)
+	}
+	if dr != e {
+		t.Errorf("expected %f but got %f", e, dr)
+	}
+	// Check that an error is thrown when the digits of the product do not fit in the degree.
+	// The higher power is 48 after 4 squarings.
+	_, err = x.Mul(x)
+	if !errors.Is(err, ErrDIsLessThanOrEqualToQPlusP) {
+		t.Errorf("expected error %v but got %v", ErrDIsLessThanOrEqualToQPlusP, err)
+	}
+}
+
+func TestEncodedAdd(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 5, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	a, _ := NewEncoded(1.5, params)
+	b, _ := NewEncoded(2.25, params)
+	prod, _ := a.Mul(b)
+	// Values with different scales can be added.
+	sum, err := prod.Add(a)
+	if err != nil {
+		t.Error(err)
+	}
+	if sum.Scale() != 4 {
+		t.Errorf("expected scale %d but got %d", 4, sum.Scale())
+	}
+	dr, err := sum.Decode()
+	if err != nil {
+		t.Error(err)
+	}
+	if dr != 4.875 {
+		t.Errorf("expected sum %f but got %f", 4.875, dr)
+	}
+	// Check that an error is thrown when the rescale is not less than the scale.
+	_, err = a.Rescale(2)
+	if !errors.Is(err, ErrRescaleIsNotLessThanScale) {
+		t.Errorf("expected error %v but got %v", ErrRescaleIsNotLessThanScale, err)
+	}
+}
+
+func TestEncodedDenominator(t *testing.T) {
+	// Create parameters (p, q, d) with the common denominator 3.
+	params, err := NewParameters(-2, 5, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params3, err := params.WithDenominator(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	third, err := EncodeRational(1, 3, params3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewEncodedFromCode(third, params3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1/3 + 1/3 = 2/3 and 1/3 x 1/3 = 1/9, with the common denominator 9.
+	sum, err := a.Add(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Rat().Cmp(big.NewRat(2, 3)) != 0 {
+		t.Errorf("expected 2/3 but got %s", sum.Rat().RatString())
+	}
+	prod, err := a.Mul(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prod.Rat().Cmp(big.NewRat(1, 9)) != 0 || prod.Parameters().Denominator() != 9 {
+		t.Errorf("expected 1/9 with denominator 9 but got %s with %d", prod.Rat().RatString(), prod.Parameters().Denominator())
+	}
+	// Values with different common denominators cannot be added.
+	_, err = prod.Add(a)
+	if !errors.Is(err, ErrDenominatorsAreDifferent) {
+		t.Errorf("expected error %v but got %v", ErrDenominatorsAreDifferent, err)
+	}
+}
+
+func TestEncodedDigitSets(t *testing.T) {
+	balanced, err := NewParameters(-2, 4, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	standard, err := NewParametersWithDigits(-2, 4, 16, DigitsStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	naf, err := NewParametersWithDigits(-2, 4, 16, DigitsNAF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewEncoded(1.5, balanced)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewEncoded(1.5, standard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewEncoded(1.5, naf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Values with different digit sets or bases cannot be combined.
+	for _, other := range []*Encoded{b, c} {
+		_, err = a.Add(other)
+		if !errors.Is(err, ErrDigitSetsAreDifferent) {
+			t.Errorf("expected error %v but got %v", ErrDigitSetsAreDifferent, err)
+		}
+		_, err = a.Sub(other)
+		if !errors.Is(err, ErrDigitSetsAreDifferent) {
+			t.Errorf("expected error %v but got %v", ErrDigitSetsAreDifferent, err)
+		}
+		_, err = a.Mul(other)
+		if !errors.Is(err, ErrDigitSetsAreDifferent) {
+			t.Errorf("expected error %v but got %v", ErrDigitSetsAreDifferent, err)
+		}
+	}
+	// Values with the same digit set keep it.
+	sum, err := c.Add(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Parameters().DigitSet() != DigitsNAF || sum.Parameters().Base() != NAFBase {
+		t.Errorf("expected the non-adjacent form but got %s", sum.Parameters())
+	}
+	if sum.Rat().Cmp(big.NewRat(3, 1)) != 0 {
+		t.Errorf("expected 3 but got %s", sum.Rat().RatString())
+	}
+}
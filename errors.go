@@ -1,6 +1,10 @@
 package polyrat
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
 
 // b is the base.
 // q is higher power.
@@ -17,4 +21,135 @@ var (
 	ErrNumeratorIsNotInTheMessageSpaceRange = errors.New("numerator should be inside the message space range")
 	ErrCodeDegreeIsNotAPowerOfTwo           = errors.New("code degree should be a power of 2")
 	ErrCodeDegreeIsDifferentFromDegree      = errors.New("code degree is different from the acceptable degree")
+	ErrBaseIsNotSupported                   = errors.New("base is different from the supported base")
+	ErrPolynomialDegreesAreDifferent        = errors.New("polynomials should have the same degree")
+	ErrCoefficientOverflow                  = errors.New("coefficient should fit in a 64-bit integer")
+	ErrNTTModulusIsNotPrime                 = errors.New("NTT modulus should be a prime")
+	ErrNTTModulusIsNotNTTFriendly           = errors.New("NTT modulus should be congruent to 1 modulo twice the degree")
+	ErrNTTModulusIsTooLarge                 = errors.New("NTT modulus should have at most 62 bits")
+	ErrNTTModulusIsTooSmall                 = errors.New("NTT modulus should be greater than twice the bound of the product coefficients")
+	ErrNTTLengthIsDifferentFromDegree       = errors.New("NTT input length is different from the degree")
+	ErrNTTPrimesAreEmpty                    = errors.New("at least one NTT prime should be given")
+	ErrNTTPrimesAreNotDistinct              = errors.New("NTT primes should be distinct")
+	ErrNTTPrimesAreNotEnough                = errors.New("not enough NTT-friendly primes of the given bit size")
+	ErrNTTBitSizeIsTooSmall                 = errors.New("NTT prime bit size should be large enough for twice the degree plus one")
+	ErrNTTPrimeCountIsLessThanOne           = errors.New("NTT prime count should be greater than or equal to 1")
+	ErrRescaleIsNotLessThanScale            = errors.New("rescale should be greater than or equal to 0 and less than the scale")
+	ErrCircuitInputsAreDifferent            = errors.New("number of inputs is different from the number of circuit inputs")
+	ErrCircuitWireIsInvalid                 = errors.New("circuit gates should only use inputs or outputs of previous gates")
+	ErrCircuitGateIsInvalid                 = errors.New("circuit gate type is invalid")
+	ErrOperationProfileIsInvalid            = errors.New("operation profile should have non-negative additions and depth, and a fan-in of at least 2")
+	ErrCoefficientsAreEmpty                 = errors.New("at least one coefficient should be given")
+	ErrCodesAreEmpty                        = errors.New("at least one code should be given")
+	ErrVectorLengthsAreDifferent            = errors.New("vectors should have the same length")
+	ErrCodeIsNotPackable                    = errors.New("code should have digits of its digit set and zero padding to be packed")
+	ErrPackedCodeLengthIsInvalid            = errors.New("packed code length is different from the length given by the parameters")
+	ErrPackedDigitIsInvalid                 = errors.New("packed code has a digit outside of its digit set")
+	ErrWireMagicIsInvalid                   = errors.New("wire data should start with the polyrat magic header")
+	ErrWireVersionIsNotSupported            = errors.New("wire format version is not supported")
+	ErrWireEncodingIsInvalid                = errors.New("wire coefficient encoding should be dense, sparse or packed")
+	ErrWirePayloadIsInvalid                 = errors.New("wire payload does not match its encoding and parameters")
+	ErrWireIsTruncated                      = errors.New("wire data is truncated")
+	ErrWireChecksumIsInvalid                = errors.New("wire checksum does not match the data")
+	ErrParametersAreMissing                 = errors.New("parameters should be given")
+	ErrCodeIndexIsInvalid                   = errors.New("code indices should be distinct and less than the degree")
+	ErrScanTypeIsNotSupported               = errors.New("scanned value should be bytes, a string or NULL")
+	ErrStreamFormatIsInvalid                = errors.New("stream format should be CSV or NDJSON")
+	ErrStreamColumnsAreEmpty                = errors.New("at least one stream column should be given")
+	ErrStreamColumnIsMissing                = errors.New("stream column is missing from the record")
+	ErrStreamRecordIsInvalid                = errors.New("stream record is malformed")
+	ErrStreamValueIsInvalid                 = errors.New("stream value should be a number or a code")
+	ErrCodecIsNotRegistered                 = errors.New("codec is not registered")
+	ErrCodecIsAlreadyRegistered             = errors.New("codec is already registered")
+	ErrPlaintextModulusIsInvalid            = errors.New("plaintext modulus should be at least 2 and have at most 62 bits")
+	ErrPlaintextIsInvalid                   = errors.New("plaintext does not belong to the backend")
+	ErrPolynomialNotationIsInvalid          = errors.New("polynomial notation should be decimal or hexadecimal")
+	ErrDigitSetIsInvalid                    = errors.New("digit set should be balanced, standard or the non-adjacent form")
+	ErrCommonDenominatorIsLessThanOne       = errors.New("common denominator should be greater than or equal to 1")
+	ErrDenominatorIsZero                    = errors.New("denominator should not be zero")
+	ErrDenominatorIsNotExact                = errors.New("rational should be a multiple of 1 / (D x b^|p|), where D is the common denominator")
+	ErrDenominatorsAreDifferent             = errors.New("encoded values should have the same common denominator")
+	ErrMaxDenominatorIsLessThanOne          = errors.New("maximum denominator should be greater than or equal to 1")
+	ErrComplexDoesNotFitInHalfTheDegree     = errors.New("higher power plus the absolute value of the lower power should be less than half the degree")
+	ErrSlotWidthIsTooSmall                  = errors.New("slot width should be greater than q + |p| + 1 to leave guard digits")
+	ErrSlotsDoNotFitInTheDegree             = errors.New("number of slots times the slot width should be less than or equal to the degree")
+	ErrPolynomialTextIsInvalid              = errors.New("polynomial text should be a sum of terms such as 5x^3")
+	ErrDIsGreaterThanMaxDegree              = errors.New("degree should be less than or equal to the maximum degree")
+	ErrEvaluationDoesNotFitInTheDegree      = errors.New("degree should be greater than the number of coefficients times q + |p| to hold the evaluation")
+	ErrDigitSetsAreDifferent                = errors.New("encoded values should have the same base and digit set")
 )
+
+// EncodeError describes a failed encoding. It carries the rational given to
+// the encoder, the numerator computed from it, the bounds of the message space
+// and a snapshot of the parameters. The underlying sentinel error can be
+// checked with errors.Is.
+type EncodeError struct {
+	Input      float64    // Input is the rational given to the encoder.
+	Numerator  *big.Int   // Numerator is the rational scaled by D x b^|p|, nil if the rational is not finite.
+	LowerBound *big.Int   // LowerBound is the smallest numerator in the message space.
+	UpperBound *big.Int   // UpperBound is the greatest numerator in the message space.
+	Params     Parameters // Params is a snapshot of the parameters used.
+	Err        error      // Err is the underlying sentinel error.
+}
+
+// newEncodeError creates an encoding error for a rational and its exact numerator.
+func newEncodeError(rat float64, numerator *big.Int, params *Parameters, err error) *EncodeError {
+	lb, ub := messageSpaceLimits(params)
+	return &EncodeError{
+		Input:      rat,
+		Numerator:  numerator,
+		LowerBound: lb,
+		UpperBound: ub,
+		Params:     *params,
+		Err:        err,
+	}
+}
+
+// Error returns the description of the encoding error.
+func (e *EncodeError) Error() string {
+	if e.Numerator == nil {
+		return fmt.Sprintf("encode %v: %v [%v, %v] (%s)",
+			e.Input, e.Err, e.LowerBound, e.UpperBound, e.Params.String())
+	}
+	return fmt.Sprintf("encode %v (numerator %v): %v [%v, %v] (%s)",
+		e.Input, e.Numerator, e.Err, e.LowerBound, e.UpperBound, e.Params.String())
+}
+
+// Unwrap returns the underlying sentinel error.
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError describes a failed decoding. It carries a copy of the code
+// given to the decoder, its length and a snapshot of the parameters. The
+// underlying sentinel error can be checked with errors.Is.
+type DecodeError struct {
+	Code       []int64    // Code is a copy of the code given to the decoder.
+	CodeLength int        // CodeLength is the number of coefficients of the code.
+	Params     Parameters // Params is a snapshot of the parameters used.
+	Err        error      // Err is the underlying sentinel error.
+}
+
+// newDecodeError creates a decoding error for a code.
+// The code is copied, so that it cannot be changed by the caller afterwards.
+func newDecodeError(code []int64, params *Parameters, err error) *DecodeError {
+	c := make([]int64, len(code))
+	copy(c, code)
+	return &DecodeError{
+		Code:       c,
+		CodeLength: len(code),
+		Params:     *params,
+		Err:        err,
+	}
+}
+
+// Error returns the description of the decoding error.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode code of length %d: %v (%s)",
+		e.CodeLength, e.Err, e.Params.String())
+}
+
+// Unwrap returns the underlying sentinel error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,103 @@
+package polyrat
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEncodeError(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Numerator 455192 is not in the message space [-555555, 444444].
+	r := 4551.92
+	_, err = Encode(r, params)
+	// Check that the sentinel error is wrapped.
+	if !errors.Is(err, ErrNumeratorIsNotInTheMessageSpaceRange) {
+		t.Errorf("expected error %v but got %v", ErrNumeratorIsNotInTheMessageSpaceRange, err)
+	}
+	// Check the context carried by the error.
+	var ee *EncodeError
+	if !errors.As(err, &ee) {
+		t.Fatalf("expected an encoding error but got %T", err)
+	}
+	if ee.Input != r {
+		t.Errorf("expected input %f but got %f", r, ee.Input)
+	}
+	if ee.Numerator.Cmp(big.NewInt(455192)) != 0 {
+		t.Errorf("expected numerator %d but got %v", 455192, ee.Numerator)
+	}
+	if ee.LowerBound.Cmp(big.NewInt(-555555)) != 0 || ee.UpperBound.Cmp(big.NewInt(444444)) != 0 {
+		t.Errorf("expected bounds [-555555, 444444] but got [%v, %v]", ee.LowerBound, ee.UpperBound)
+	}
+	if ee.Params.MinPower() != -2 || ee.Params.MaxPower() != 3 || ee.Params.Degree() != 16 {
+		t.Errorf("expected parameters (b=10, p=-2, q=3, d=16) but got (%s)", ee.Params.String())
+	}
+}
+
+func TestEncodeErrorExact(t *testing.T) {
+	// Create parameters (p, q, d) with 16 digits, whose bounds are not exact in float64.
+	params, err := NewParameters(-4, 11, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The numerator of 1e300 does not fit in 64 bits, so it is reported exactly.
+	_, err = Encode(1e300, params)
+	var ee *EncodeError
+	if !errors.As(err, &ee) {
+		t.Fatalf("expected an encoding error but got %v", err)
+	}
+	en, _ := new(big.Float).Mul(big.NewFloat(1e300), big.NewFloat(1e4)).Int(nil)
+	if ee.Numerator == nil || ee.Numerator.Cmp(en) != 0 {
+		t.Errorf("expected numerator %v but got %v", en, ee.Numerator)
+	}
+	lb, _ := new(big.Int).SetString("-5555555555555555", 10)
+	ub, _ := new(big.Int).SetString("4444444444444444", 10)
+	if ee.LowerBound.Cmp(lb) != 0 || ee.UpperBound.Cmp(ub) != 0 {
+		t.Errorf("expected bounds [%v, %v] but got [%v, %v]", lb, ub, ee.LowerBound, ee.UpperBound)
+	}
+	// Rationals that are not finite have no numerator.
+	_, err = Encode(math.Inf(1), params)
+	if !errors.As(err, &ee) || ee.Numerator != nil {
+		t.Errorf("expected an encoding error without numerator but got %v", err)
+	}
+	if strings.HasPrefix(err.Error(), "polyrat:") {
+		t.Errorf("expected an error without prefix but got %q", err.Error())
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Code with a degree that is not a power of 2.
+	c := []int64{2, -3, 0, 0, -3, 0, 1}
+	_, err = Decode(c, params)
+	// Check that the sentinel error is wrapped.
+	if !errors.Is(err, ErrCodeDegreeIsNotAPowerOfTwo) {
+		t.Errorf("expected error %v but got %v", ErrCodeDegreeIsNotAPowerOfTwo, err)
+	}
+	// Check the context carried by the error.
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected a decoding error but got %T", err)
+	}
+	if de.CodeLength != len(c) {
+		t.Errorf("expected code length %d but got %d", len(c), de.CodeLength)
+	}
+	// The code is a copy of the input.
+	c[0] = 7
+	if len(de.Code) != len(c) || de.Code[0] != 2 || de.Code[6] != 1 {
+		t.Errorf("expected a copy of the code but got %v", de.Code)
+	}
+	if de.Params.Degree() != 16 {
+		t.Errorf("expected degree %d but got %d", 16, de.Params.Degree())
+	}
+}
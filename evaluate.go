@@ -0,0 +1,78 @@
+package polyrat
+
+import (
+	"math/big"
+)
+
+// EvaluatePolynomial evaluates a polynomial with rational coefficients on an encoded value.
+// The coefficients are given from the constant term to the leading term and are truncated to
+// the precision of the parameters, as in Encode. See EvaluatePolynomialRat.
+func EvaluatePolynomial(coeffs []float64, code []int64, params *Parameters) (*Encoded, error) {
+	fs := make([]*big.Rat, len(coeffs))
+	for i, c := range coeffs {
+		fs[i] = rationalToFraction(c, params)
+	}
+	return EvaluatePolynomialRat(fs, code, params)
+}
+
+// EvaluatePolynomialRat evaluates a polynomial with exact rational coefficients on an encoded value.
+// The coefficients are given from the constant term to the leading term and are encoded with the
+// same parameters as the code. The evaluation uses Horner's rule in Z[X]/(X^d + 1) and is exact,
+// so the digits of the result occupy the powers from (n + 1) x p to (n + 1) x q for a polynomial
+// of degree n, and the degree should be greater than (n + 1) x (q + |p|). An error is returned if
+// the result does not fit in the degree or if an intermediate value is not in the message space.
+func EvaluatePolynomialRat(coeffs []*big.Rat, code []int64, params *Parameters) (*Encoded, error) {
+	if len(coeffs) == 0 {
+		return nil, ErrCoefficientsAreEmpty
+	}
+	// d > (n + 1) x (q + |p|).
+	if len(coeffs) > (params.Degree()-1)/(params.MaxPower()-params.MinPower()) {
+		return nil, ErrEvaluationDoesNotFitInTheDegree
+	}
+	x, err := NewEncodedFromCode(code, params)
+	if err != nil {
+		return nil, err
+	}
+	// Encode coefficients.
+	cs := make([]*Encoded, len(coeffs))
+	for i, c := range coeffs {
+		cc, err := encodeFraction(c, params)
+		if err != nil {
+			return nil, err
+		}
+		cs[i], err = NewEncodedFromCode(cc, params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Horner's rule: acc = (...(c_n x + c_(n-1)) x + ...) x + c_0.
+	acc := cs[len(cs)-1]
+	for i := len(cs) - 2; i >= 0; i-- {
+		acc, err = acc.Mul(x)
+		if err != nil {
+			return nil, err
+		}
+		err = checkMessageSpace(acc, params)
+		if err != nil {
+			return nil, err
+		}
+		acc, err = acc.Add(cs[i])
+		if err != nil {
+			return nil, err
+		}
+		err = checkMessageSpace(acc, params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// checkMessageSpace checks that an encoded value, truncated to the precision of the parameters, is in their message space.
+func checkMessageSpace(enc *Encoded, params *Parameters) error {
+	n, ok := fractionNumerator(enc.Rat(), params)
+	if !ok || inputIsInvalid(n, params) {
+		return ErrNumeratorIsNotInTheMessageSpaceRange
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package polyrat
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestEvaluatePolynomial(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 4, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	// 0.5x^2 + 3.25x - 1.
+	coeffs := []float64{-1, 3.25, 0.5}
+	r := []float64{0, 1, -2.5, 12.34, -99.99}
+	for i := 0; i < len(r); i++ {
+		c, err := Encode(r[i], params)
+		if err != nil {
+			t.Error(err)
+		}
+		enc, err := EvaluatePolynomial(coeffs, c, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Degree 2: the scale is 3 x |p|.
+		if enc.Scale() != 6 {
+			t.Errorf("expected scale %d but got %d", 6, enc.Scale())
+		}
+		// Exact expected value.
+		x := rationalToFraction(r[i], params)
+		e := new(big.Rat).Mul(x, x)
+		e.Mul(e, big.NewRat(1, 2))
+		e.Add(e, new(big.Rat).Mul(x, big.NewRat(13, 4)))
+		e.Sub(e, big.NewRat(1, 1))
+		if enc.Rat().Cmp(e) != 0 {
+			t.Errorf("expected %s but got %s", e.FloatString(6), enc.Rat().FloatString(6))
+		}
+		ef, _ := e.Float64()
+		dr, err := enc.Decode()
+		if err != nil {
+			t.Error(err)
+		}
+		if dr != ef {
+			t.Errorf("expected %f but got %f", ef, dr)
+		}
+	}
+}
+
+func TestEvaluatePolynomialRat(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-3, 4, 64)
+	if err != nil {
+		t.Error(err)
+	}
+	// x^3 / 8 - x, with x = 2: 1 - 2 = -1.
+	coeffs := []*big.Rat{big.NewRat(0, 1), big.NewRat(-1, 1), big.NewRat(0, 1), big.NewRat(1, 8)}
+	c, err := Encode(2, params)
+	if err != nil {
+		t.Error(err)
+	}
+	enc, err := EvaluatePolynomialRat(coeffs, c, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc.Rat().Cmp(big.NewRat(-1, 1)) != 0 {
+		t.Errorf("expected %d but got %s", -1, enc.Rat().String())
+	}
+	// 1/3 is truncated to 0.333.
+	coeffs = []*big.Rat{big.NewRat(1, 3)}
+	enc, err = EvaluatePolynomialRat(coeffs, c, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc.Rat().Cmp(big.NewRat(333, 1000)) != 0 {
+		t.Errorf("expected %s but got %s", "333/1000", enc.Rat().String())
+	}
+}
+
+func TestEvaluatePolynomialOverflow(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 2, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	c, err := Encode(300, params)
+	if err != nil {
+		t.Error(err)
+	}
+	// Check that an error is thrown when x^2 is not in the message space [-555.55, 444.44].
+	_, err = EvaluatePolynomial([]float64{0, 0, 1}, c, params)
+	if !errors.Is(err, ErrNumeratorIsNotInTheMessageSpaceRange) {
+		t.Errorf("expected error %v but got %v", ErrNumeratorIsNotInTheMessageSpaceRange, err)
+	}
+	// Check that an error is thrown when the result does not fit in the degree: 3 x (11 + 4) >= 32.
+	params, err = NewParameters(-4, 11, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	c, err = Encode(1.5, params)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = EvaluatePolynomial([]float64{-1, 3.25, 0.5}, c, params)
+	if !errors.Is(err, ErrEvaluationDoesNotFitInTheDegree) {
+		t.Errorf("expected error %v but got %v", ErrEvaluationDoesNotFitInTheDegree, err)
+	}
+	// Check that an error is thrown when there are no coefficients.
+	_, err = EvaluatePolynomial(nil, c, params)
+	if !errors.Is(err, ErrCoefficientsAreEmpty) {
+		t.Errorf("expected error %v but got %v", ErrCoefficientsAreEmpty, err)
+	}
+}
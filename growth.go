@@ -0,0 +1,101 @@
+package polyrat
+
+import (
+	"math/big"
+)
+
+// ExpectedBoundDeviations is the number of standard deviations used for the expected coefficient bound.
+const ExpectedBoundDeviations = 6
+
+// OperationProfile describes the shape of a computation on encoded values.
+// Fresh codes are first summed in groups of Additions + 1 values, and the sums
+// are then multiplied in Depth levels, every multiplication having FanIn factors.
+type OperationProfile struct {
+	Additions int // Additions is the number of additions before the first multiplication.
+	Depth     int // Depth is the multiplicative depth.
+	FanIn     int // FanIn is the number of factors of every multiplication.
+}
+
+// GrowthEstimate contains the coefficient bounds of the result of a computation.
+type GrowthEstimate struct {
+	Worst           *big.Int    // Worst is the worst-case bound of the absolute value of the coefficients.
+	Expected        *big.Int    // Expected is the bound of ExpectedBoundDeviations standard deviations.
+	Modulus         *big.Int    // Modulus is the smallest plaintext modulus t that decodes the worst case.
+	ExpectedModulus *big.Int    // ExpectedModulus is the smallest plaintext modulus t that decodes the expected case.
+	Parameters      *Parameters // Parameters describe the powers occupied by the digits of the result.
+}
+
+// EstimateGrowth estimates the coefficient growth of a computation on codes generated with the given parameters.
+// Coefficients are decoded in the centered range (-t/2, t/2], so the plaintext modulus t should be greater than
+// twice the bound. The expected bound assumes independent digits distributed uniformly in the digit set.
+// An error is returned if the digits of the result do not fit in the degree.
+func EstimateGrowth(params *Parameters, profile OperationProfile) (*GrowthEstimate, error) {
+	// Validate profile.
+	if profile.Additions < 0 || profile.Depth < 0 || (profile.Depth > 0 && profile.FanIn < 2) {
+		return nil, ErrOperationProfileIsInvalid
+	}
+	b := int64(params.Base())
+	// Fresh digits: |x| <= b/2 and E[x^2] = (1/b) x sum of x^2 for x in [-b/2, b/2).
+	// Standard digits have |x| <= b-1 and the same second moment as x in [0, b).
+	lo, hi := -b/2, b-b/2
+	worst := big.NewInt(b / 2)
+	if params.DigitSet() == DigitsStandard {
+		lo, hi = 0, b
+		worst.SetInt64(b - 1)
+	}
+	m2 := new(big.Int)
+	for x := lo; x < hi; x++ {
+		m2.Add(m2, big.NewInt(x*x))
+	}
+	moment := new(big.Float).Quo(new(big.Float).SetInt(m2), new(big.Float).SetInt64(b))
+	// Digits of the non-adjacent form have |x| <= 1 and a density of nonzero digits of 1/3, so E[x^2] = 1/3.
+	if params.DigitSet() == DigitsNAF {
+		worst.SetInt64(1)
+		moment.SetFloat64(1.0 / 3)
+	}
+	// Additions: the bound and the second moment are multiplied by the number of terms.
+	terms := big.NewInt(int64(profile.Additions + 1))
+	worst.Mul(worst, terms)
+	moment.Mul(moment, new(big.Float).SetInt(terms))
+	// Multiplications: every coefficient of a product is a sum of at most n products of coefficients,
+	// where n is the number of powers occupied by the digits.
+	layout := params
+	for level := 0; level < profile.Depth; level++ {
+		factorWorst := new(big.Int).Set(worst)
+		factorMoment := new(big.Float).Set(moment)
+		factorLayout := layout
+		for f := 1; f < profile.FanIn; f++ {
+			n := int64(minInt(polynomialLength(layout), polynomialLength(factorLayout)))
+			worst.Mul(worst, factorWorst)
+			worst.Mul(worst, big.NewInt(n))
+			moment.Mul(moment, factorMoment)
+			moment.Mul(moment, new(big.Float).SetInt64(n))
+			// Digits of the product.
+			var err error
+			layout, err = NewParametersWithDigits(layout.MinPower()+factorLayout.MinPower(),
+				layout.MaxPower()+factorLayout.MaxPower(), params.Degree(), params.DigitSet())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Expected bound: deviations x sqrt(E[x^2]), limited by the worst case.
+	expected, _ := moment.Sqrt(moment).Mul(moment, new(big.Float).SetInt64(ExpectedBoundDeviations)).Int(nil)
+	expected.Add(expected, big.NewInt(1))
+	if expected.Cmp(worst) > 0 {
+		expected.Set(worst)
+	}
+	estimate := new(GrowthEstimate)
+	estimate.Worst = worst
+	estimate.Expected = expected
+	estimate.Modulus = plaintextModulus(worst)
+	estimate.ExpectedModulus = plaintextModulus(expected)
+	estimate.Parameters = layout
+	return estimate, nil
+}
+
+// plaintextModulus returns the smallest modulus t such that [-bound, bound] is inside (-t/2, t/2], that is, 2 x bound + 1.
+func plaintextModulus(bound *big.Int) *big.Int {
+	t := new(big.Int).Lsh(bound, 1)
+	return t.Add(t, big.NewInt(1))
+}
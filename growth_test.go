@@ -0,0 +1,155 @@
+package polyrat
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestEstimateGrowthAdditions(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Sum of 4 fresh codes: |c| <= 4 x 5.
+	estimate, err := EstimateGrowth(params, OperationProfile{Additions: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Worst.Cmp(big.NewInt(20)) != 0 {
+		t.Errorf("expected worst bound %d but got %s", 20, estimate.Worst.String())
+	}
+	if estimate.Modulus.Cmp(big.NewInt(41)) != 0 {
+		t.Errorf("expected modulus %d but got %s", 41, estimate.Modulus.String())
+	}
+	if estimate.Expected.Cmp(estimate.Worst) > 0 {
+		t.Errorf("expected bound %s should not be greater than the worst bound %s", estimate.Expected.String(), estimate.Worst.String())
+	}
+}
+
+func TestEstimateGrowthStandardDigits(t *testing.T) {
+	// Create parameters (p, q, d) with standard digits.
+	params, err := NewParametersWithDigits(-2, 3, 16, DigitsStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sum of 4 fresh codes: |c| <= 4 x 9.
+	estimate, err := EstimateGrowth(params, OperationProfile{Additions: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Worst.Cmp(big.NewInt(36)) != 0 {
+		t.Errorf("expected worst bound %d but got %s", 36, estimate.Worst.String())
+	}
+	if estimate.Modulus.Cmp(big.NewInt(73)) != 0 {
+		t.Errorf("expected modulus %d but got %s", 73, estimate.Modulus.String())
+	}
+}
+
+func TestEstimateGrowthNAF(t *testing.T) {
+	// Create parameters (p, q, d) with the non-adjacent form.
+	params, err := NewParametersWithDigits(-2, 3, 16, DigitsNAF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sum of 4 fresh codes: |c| <= 4 x 1.
+	estimate, err := EstimateGrowth(params, OperationProfile{Additions: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Worst.Cmp(big.NewInt(4)) != 0 {
+		t.Errorf("expected worst bound %d but got %s", 4, estimate.Worst.String())
+	}
+	if estimate.Modulus.Cmp(big.NewInt(9)) != 0 {
+		t.Errorf("expected modulus %d but got %s", 9, estimate.Modulus.String())
+	}
+}
+
+func TestEstimateGrowthMultiplications(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-1, 1, 8)
+	if err != nil {
+		t.Error(err)
+	}
+	// Square of a code with 3 digits: |c| <= 3 x 5 x 5.
+	estimate, err := EstimateGrowth(params, OperationProfile{Depth: 1, FanIn: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Worst.Cmp(big.NewInt(75)) != 0 {
+		t.Errorf("expected worst bound %d but got %s", 75, estimate.Worst.String())
+	}
+	if estimate.Parameters.MinPower() != -2 || estimate.Parameters.MaxPower() != 2 {
+		t.Errorf("expected powers from %d to %d but got (%s)", -2, 2, estimate.Parameters.String())
+	}
+	// The bound is attained by -55.5, whose digits are all -5.
+	x, err := NewEncoded(-55.5, params)
+	if err != nil {
+		t.Error(err)
+	}
+	x, err = x.Mul(x)
+	if err != nil {
+		t.Error(err)
+	}
+	if m := maxAbs(x.Code()); m != 75 {
+		t.Errorf("expected greatest coefficient %d but got %d", 75, m)
+	}
+}
+
+func TestEstimateGrowthBound(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 2, 64)
+	if err != nil {
+		t.Error(err)
+	}
+	// ((x0 + x1) x (x2 + x3)) x ((x4 + x5) x (x6 + x7)).
+	estimate, err := EstimateGrowth(params, OperationProfile{Additions: 1, Depth: 2, FanIn: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := []float64{-555.55, 444.44, -123.45, 404.04, -555.55, -555.55, 321.09, -98.76}
+	var x []*Encoded
+	for i := 0; i < len(r); i += 2 {
+		a, err := NewEncoded(r[i], params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := NewEncoded(r[i+1], params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, _ := a.Add(b)
+		x = append(x, s)
+	}
+	m0, _ := x[0].Mul(x[1])
+	m1, _ := x[2].Mul(x[3])
+	m, err := m0.Mul(m1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if new(big.Int).SetUint64(maxAbs(m.Code())).Cmp(estimate.Worst) > 0 {
+		t.Errorf("coefficient %d is greater than the worst bound %s", maxAbs(m.Code()), estimate.Worst.String())
+	}
+	if m.Scale() != -estimate.Parameters.MinPower() {
+		t.Errorf("expected scale %d but got %d", -estimate.Parameters.MinPower(), m.Scale())
+	}
+}
+
+func TestEstimateGrowthErrors(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Check that an error is thrown when the fan-in is less than 2.
+	_, err = EstimateGrowth(params, OperationProfile{Depth: 1, FanIn: 1})
+	if !errors.Is(err, ErrOperationProfileIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrOperationProfileIsInvalid, err)
+	}
+	// Check that an error is thrown when the digits of the result do not fit in the degree.
+	_, err = EstimateGrowth(params, OperationProfile{Depth: 2, FanIn: 2})
+	if !errors.Is(err, ErrDIsLessThanOrEqualToQPlusP) {
+		t.Errorf("expected error %v but got %v", ErrDIsLessThanOrEqualToQPlusP, err)
+	}
+}
@@ -0,0 +1,110 @@
+package polyrat
+
+import (
+	"math/bits"
+)
+
+// packedDigitRange returns the range [lo, hi) of the packed digits.
+// Balanced digits are in [-b/2, b/2), standard digits share the sign of the value and are
+// in (-b, b), and digits of the non-adjacent form are in {-1, 0, 1}.
+func packedDigitRange(params *Parameters) (int64, int64) {
+	b := int64(params.Base())
+	switch params.DigitSet() {
+	case DigitsStandard:
+		return -(b - 1), b
+	case DigitsNAF:
+		return -1, 2
+	}
+	return -b / 2, b - b/2
+}
+
+// digitBits returns the number of bits of a packed digit: ceil(log2 b) for balanced digits,
+// ceil(log2 (2b - 1)) for standard digits and 2 for digits of the non-adjacent form.
+func digitBits(params *Parameters) int {
+	lo, hi := packedDigitRange(params)
+	return bits.Len64(uint64(hi - lo - 1))
+}
+
+// PackedCodeLength returns the number of bytes of a code marshalled with MarshalCode.
+func PackedCodeLength(params *Parameters) int {
+	return (polynomialLength(params)*digitBits(params) + 7) / 8
+}
+
+// MarshalCode bit-packs a code generated with the given parameters.
+// Only the q + 1 integer digits and the |p| fractional digits are stored, starting with
+// the integer digits, and the zero padding between them is left out. Each digit d is
+// stored as d - lo in the bits of its digit set, where lo is its smallest digit: balanced
+// digits are stored as d + b/2 in ceil(log2 b) bits, standard digits, in (-b, b), as
+// d + b - 1 in ceil(log2 (2b - 1)) bits, and digits of the non-adjacent form, in {-1, 0, 1},
+// as d + 1 in 2 bits.
+func MarshalCode(code []int64, params *Parameters) ([]byte, error) {
+	// Validate degree of code.
+	err := validateDegreeOfCode(code, params)
+	if err != nil {
+		return nil, err
+	}
+	// Validate zero padding.
+	d, q, p := params.Degree(), params.MaxPower(), params.MinPower()
+	for i := q + 1; i < d+p; i++ {
+		if code[i] != 0 {
+			return nil, ErrCodeIsNotPackable
+		}
+	}
+	w := digitBits(params)
+	lo, hi := packedDigitRange(params)
+	data := make([]byte, PackedCodeLength(params))
+	for i, digit := range codeDigits(code, params) {
+		// Digits should be in [lo, hi).
+		if digit < lo || digit >= hi {
+			return nil, ErrCodeIsNotPackable
+		}
+		writeBits(data, i*w, uint64(digit-lo))
+	}
+	return data, nil
+}
+
+// UnmarshalCode restores a code marshalled with MarshalCode, with the same layout generated by Encode.
+func UnmarshalCode(data []byte, params *Parameters) ([]int64, error) {
+	if len(data) != PackedCodeLength(params) {
+		return nil, ErrPackedCodeLengthIsInvalid
+	}
+	w := digitBits(params)
+	lo, hi := packedDigitRange(params)
+	l := polynomialLength(params)
+	// Padding bits should be zero.
+	if l*w < len(data)*8 && readBits(data, l*w, len(data)*8-l*w).Sign() != 0 {
+		return nil, ErrPackedDigitIsInvalid
+	}
+	digits := make([]int64, l)
+	for i := 0; i < l; i++ {
+		v := readBits(data, i*w, w).Int64()
+		if v >= hi-lo {
+			return nil, ErrPackedDigitIsInvalid
+		}
+		digits[i] = v + lo
+	}
+	return digitsCode(digits, params), nil
+}
+
+// codeDigits returns the integer digits followed by the fractional digits of a code.
+// Fractional digits are stored negated in the upper coefficients of the code.
+func codeDigits(code []int64, params *Parameters) []int64 {
+	d, q, p := params.Degree(), params.MaxPower(), params.MinPower()
+	digits := make([]int64, 0, polynomialLength(params))
+	digits = append(digits, code[:q+1]...)
+	for i := d + p; i < d; i++ {
+		digits = append(digits, -code[i])
+	}
+	return digits
+}
+
+// digitsCode places integer and fractional digits in the layout of a code.
+func digitsCode(digits []int64, params *Parameters) []int64 {
+	d, q, p := params.Degree(), params.MaxPower(), params.MinPower()
+	code := make([]int64, d)
+	copy(code, digits[:q+1])
+	for i := 0; i < -p; i++ {
+		code[d+p+i] = -digits[q+1+i]
+	}
+	return code
+}
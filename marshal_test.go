@@ -0,0 +1,175 @@
+package polyrat
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMarshalCode(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-4, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Code of rational 98123.45.
+	c := []int64{4, 2, 1, -2, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 5, 5}
+	data, err := MarshalCode(c, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 16 digits of 4 bits.
+	if len(data) != 8 {
+		t.Errorf("expected %d bytes but got %d", 8, len(data))
+	}
+	// Digits 4 and 2 are stored as 9 and 7.
+	if data[0] != 0x79 {
+		t.Errorf("expected first byte %x but got %x", 0x79, data[0])
+	}
+	uc, err := UnmarshalCode(data, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(c); i++ {
+		if uc[i] != c[i] {
+			t.Errorf("expected code value %d at position %d but got %d", c[i], i, uc[i])
+		}
+	}
+}
+
+func TestMarshalCodeRoundTrip(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-8, 20, 2048)
+	if err != nil {
+		t.Error(err)
+	}
+	// 29 digits of 4 bits instead of 2048 x 8 bytes.
+	if PackedCodeLength(params) != 15 {
+		t.Errorf("expected %d bytes but got %d", 15, PackedCodeLength(params))
+	}
+	r := []float64{83740034.866, -83740034.866, 0, -0.00000001, -55555555555.55555555}
+	for i := 0; i < len(r); i++ {
+		c, err := Encode(r[i], params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := MarshalCode(c, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uc, err := UnmarshalCode(data, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dr, err := Decode(uc, params)
+		if err != nil {
+			t.Error(err)
+		}
+		er, _ := Decode(c, params)
+		if dr != er {
+			t.Errorf("expected %f but got %f", er, dr)
+		}
+	}
+}
+
+func TestMarshalCodeStandard(t *testing.T) {
+	// Create parameters (p, q, d) with standard digits.
+	params, err := NewParametersWithDigits(-2, 3, 16, DigitsStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 6 digits of 5 bits, since digits are in (-10, 10).
+	if PackedCodeLength(params) != 4 {
+		t.Errorf("expected %d bytes but got %d", 4, PackedCodeLength(params))
+	}
+	for _, r := range []float64{9999.99, -9999.99, 1234.56, -1234.56, 0} {
+		c, err := Encode(r, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := MarshalCode(c, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uc, err := UnmarshalCode(data, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range c {
+			if uc[i] != c[i] {
+				t.Fatalf("expected code %v but got %v", c, uc)
+			}
+		}
+	}
+	// Check that an error is thrown when a digit is not in (-b, b).
+	c := []int64{10, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	_, err = MarshalCode(c, params)
+	if !errors.Is(err, ErrCodeIsNotPackable) {
+		t.Errorf("expected error %v but got %v", ErrCodeIsNotPackable, err)
+	}
+}
+
+func TestMarshalCodeNAF(t *testing.T) {
+	// Create parameters (p, q, d) with the non-adjacent form.
+	params, err := NewParametersWithDigits(-2, 5, 16, DigitsNAF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 8 digits of 2 bits.
+	if PackedCodeLength(params) != 2 {
+		t.Errorf("expected %d bytes but got %d", 2, PackedCodeLength(params))
+	}
+	for _, r := range []float64{5.25, -3.75, 42, -42, 0} {
+		c, err := Encode(r, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := MarshalCode(c, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uc, err := UnmarshalCode(data, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range c {
+			if uc[i] != c[i] {
+				t.Fatalf("expected code %v but got %v", c, uc)
+			}
+		}
+	}
+	// The value 3 is not a digit of the non-adjacent form.
+	_, err = UnmarshalCode([]byte{3, 0}, params)
+	if !errors.Is(err, ErrPackedDigitIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrPackedDigitIsInvalid, err)
+	}
+}
+
+func TestMarshalCodeErrors(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Check that an error is thrown when the padding is not zero.
+	c := []int64{1, 0, 0, 0, 0, 7, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	_, err = MarshalCode(c, params)
+	if !errors.Is(err, ErrCodeIsNotPackable) {
+		t.Errorf("expected error %v but got %v", ErrCodeIsNotPackable, err)
+	}
+	// Check that an error is thrown when a digit is not in [-b/2, b/2).
+	c = []int64{5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	_, err = MarshalCode(c, params)
+	if !errors.Is(err, ErrCodeIsNotPackable) {
+		t.Errorf("expected error %v but got %v", ErrCodeIsNotPackable, err)
+	}
+	// Check that an error is thrown when the length is different.
+	_, err = UnmarshalCode([]byte{0, 0}, params)
+	if !errors.Is(err, ErrPackedCodeLengthIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrPackedCodeLengthIsInvalid, err)
+	}
+	// Check that an error is thrown when a packed digit is greater than the base.
+	_, err = UnmarshalCode([]byte{0xff, 0x55, 0x55}, params)
+	if !errors.Is(err, ErrPackedDigitIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrPackedDigitIsInvalid, err)
+	}
+}
@@ -0,0 +1,274 @@
+package polyrat
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// karatsubaThreshold is the length under which Karatsuba multiplication falls back to schoolbook multiplication.
+const karatsubaThreshold = 32
+
+// Mul returns the product of two polynomials in Z[X]/(X^d + 1).
+// Coefficients are computed with 64-bit integers when the worst case fits,
+// otherwise they are computed exactly with big integers.
+// An error is returned if a coefficient of the product does not fit in a 64-bit integer.
+func (poly *Polynomial) Mul(other *Polynomial) (*Polynomial, error) {
+	// Check that both polynomials belong to the same ring.
+	if poly.d != other.d {
+		return nil, ErrPolynomialDegreesAreDifferent
+	}
+	prod := poly.zero()
+	// Fast path with 64-bit integers.
+	if productFitsInt64(poly.c, other.c) {
+		if poly.d <= karatsubaThreshold {
+			prod.c = mulSchoolbook(poly.c, other.c)
+		} else {
+			prod.c = mulKaratsuba(poly.c, other.c)
+		}
+		return prod, nil
+	}
+	// Exact path with big integers.
+	c, err := poly.MulBig(other)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < poly.d; i++ {
+		if !c[i].IsInt64() {
+			return nil, ErrCoefficientOverflow
+		}
+		prod.c[i] = c[i].Int64()
+	}
+	return prod, nil
+}
+
+// MulBig returns the exact coefficients of the product of two polynomials in Z[X]/(X^d + 1).
+func (poly *Polynomial) MulBig(other *Polynomial) ([]*big.Int, error) {
+	// Check that both polynomials belong to the same ring.
+	if poly.d != other.d {
+		return nil, ErrPolynomialDegreesAreDifferent
+	}
+	return mulKronecker(poly.c, other.c), nil
+}
+
+// productFitsInt64 checks if the worst case of every intermediate value of the
+// Karatsuba multiplication fits in a 64-bit integer.
+// The recursion sums halves of the factors, so the bound is d^2 x max|a| x max|b|.
+func productFitsInt64(a, b []int64) bool {
+	l := bits.Len64(maxAbs(a)) + bits.Len64(maxAbs(b)) + 2*bits.Len(uint(len(a)))
+	return l < 63
+}
+
+// maxAbs returns the greatest absolute value of the coefficients.
+func maxAbs(a []int64) uint64 {
+	var m uint64
+	for _, v := range a {
+		// The conversion of the negation handles the smallest 64-bit integer.
+		abs := uint64(v)
+		if v < 0 {
+			abs = uint64(-v)
+		}
+		if abs > m {
+			m = abs
+		}
+	}
+	return m
+}
+
+// mulSchoolbook is the reference negacyclic multiplication.
+// Terms with power i+j >= d wrap around with a negated sign since X^d = -1.
+func mulSchoolbook(a, b []int64) []int64 {
+	d := len(a)
+	c := make([]int64, d)
+	for i := 0; i < d; i++ {
+		if a[i] == 0 {
+			continue
+		}
+		for j := 0; j < d; j++ {
+			if i+j < d {
+				c[i+j] += a[i] * b[j]
+			} else {
+				c[i+j-d] -= a[i] * b[j]
+			}
+		}
+	}
+	return c
+}
+
+// mulKaratsuba computes the negacyclic product by reducing the linear Karatsuba product modulo X^d + 1.
+func mulKaratsuba(a, b []int64) []int64 {
+	d := len(a)
+	c := make([]int64, d)
+	lp := karatsuba(a, b)
+	for i := 0; i < len(lp); i++ {
+		if i < d {
+			c[i] += lp[i]
+		} else {
+			c[i-d] -= lp[i]
+		}
+	}
+	return c
+}
+
+// karatsuba computes the linear product of two polynomials with the same power of 2 length.
+// The product has 2n - 1 coefficients.
+func karatsuba(a, b []int64) []int64 {
+	n := len(a)
+	if n <= karatsubaThreshold {
+		return linearSchoolbook(a, b)
+	}
+	// Split the factors in lower and upper halves.
+	h := n / 2
+	a0, a1 := a[:h], a[h:]
+	b0, b1 := b[:h], b[h:]
+	// Products of the halves.
+	z0 := karatsuba(a0, b0)
+	z2 := karatsuba(a1, b1)
+	// Product of the sums of the halves.
+	as := make([]int64, h)
+	bs := make([]int64, h)
+	for i := 0; i < h; i++ {
+		as[i] = a0[i] + a1[i]
+		bs[i] = b0[i] + b1[i]
+	}
+	z1 := karatsuba(as, bs)
+	// Middle term: (a0 + a1)(b0 + b1) - a0b0 - a1b1.
+	for i := 0; i < len(z1); i++ {
+		z1[i] -= z0[i] + z2[i]
+	}
+	// Combine the terms: z0 + z1 X^h + z2 X^n.
+	c := make([]int64, 2*n-1)
+	for i := 0; i < len(z0); i++ {
+		c[i] += z0[i]
+		c[i+h] += z1[i]
+		c[i+n] += z2[i]
+	}
+	return c
+}
+
+// linearSchoolbook computes the linear product of two polynomials with the same length.
+func linearSchoolbook(a, b []int64) []int64 {
+	n := len(a)
+	c := make([]int64, 2*n-1)
+	for i := 0; i < n; i++ {
+		if a[i] == 0 {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			c[i+j] += a[i] * b[j]
+		}
+	}
+	return c
+}
+
+// mulKronecker computes the exact negacyclic product with Kronecker substitution.
+// Both polynomials are evaluated at X = 2^k, the integers are multiplied with
+// math/big, and the coefficients are read back from slots of k bits.
+func mulKronecker(a, b []int64) []*big.Int {
+	d := len(a)
+	c := make([]*big.Int, d)
+	for i := 0; i < d; i++ {
+		c[i] = new(big.Int)
+	}
+	ma, mb := maxAbs(a), maxAbs(b)
+	if ma == 0 || mb == 0 {
+		return c
+	}
+	// Each coefficient of the linear product is less than 2^(k-1) in absolute value.
+	k := bits.Len64(ma) + bits.Len64(mb) + bits.Len(uint(d)) + 1
+	// Linear product.
+	w := new(big.Int).Mul(kroneckerPack(a, k), kroneckerPack(b, k))
+	lp := kroneckerUnpack(w, k, 2*d-1)
+	// Reduction modulo X^d + 1.
+	for i := 0; i < len(lp); i++ {
+		if i < d {
+			c[i].Add(c[i], lp[i])
+		} else {
+			c[i-d].Sub(c[i-d], lp[i])
+		}
+	}
+	return c
+}
+
+// kroneckerPack evaluates a polynomial at X = 2^k.
+// Positive and negative coefficients are packed separately and subtracted.
+func kroneckerPack(a []int64, k int) *big.Int {
+	l := (len(a)*k+7)/8 + 1
+	pos := make([]byte, l)
+	neg := make([]byte, l)
+	for i, v := range a {
+		if v > 0 {
+			writeBits(pos, i*k, uint64(v))
+		} else if v < 0 {
+			writeBits(neg, i*k, uint64(-v))
+		}
+	}
+	p := new(big.Int).SetBytes(reverseBytes(pos))
+	n := new(big.Int).SetBytes(reverseBytes(neg))
+	return p.Sub(p, n)
+}
+
+// kroneckerUnpack reads n balanced coefficients from slots of k bits of an integer.
+func kroneckerUnpack(w *big.Int, k, n int) []*big.Int {
+	// Coefficients are read from the absolute value and negated at the end.
+	neg := w.Sign() < 0
+	buf := reverseBytes(new(big.Int).Abs(w).Bytes())
+	// Padding so that every slot can be read.
+	l := (n*k+7)/8 + 1
+	if len(buf) < l {
+		buf = append(buf, make([]byte, l-len(buf))...)
+	}
+	half := new(big.Int).Lsh(big.NewInt(1), uint(k-1))
+	full := new(big.Int).Lsh(big.NewInt(1), uint(k))
+	c := make([]*big.Int, n)
+	carry := 0
+	for i := 0; i < n; i++ {
+		u := readBits(buf, i*k, k)
+		u.Add(u, big.NewInt(int64(carry)))
+		// Balanced digit: slots greater than or equal to 2^(k-1) are negative.
+		carry = 0
+		if u.Cmp(half) >= 0 {
+			u.Sub(u, full)
+			carry = 1
+		}
+		if neg {
+			u.Neg(u)
+		}
+		c[i] = u
+	}
+	return c
+}
+
+// writeBits writes a value at a bit offset of a little-endian buffer.
+func writeBits(buf []byte, offset int, v uint64) {
+	for v != 0 {
+		s := offset % 8
+		buf[offset/8] |= byte(v << s)
+		v >>= 8 - s
+		offset += 8 - s
+	}
+}
+
+// readBits reads k bits at a bit offset of a little-endian buffer.
+func readBits(buf []byte, offset, k int) *big.Int {
+	lo := offset / 8
+	hi := (offset+k)/8 + 1
+	if hi > len(buf) {
+		hi = len(buf)
+	}
+	// Bytes are copied because they are reversed to big-endian order.
+	b := make([]byte, hi-lo)
+	copy(b, buf[lo:hi])
+	v := new(big.Int).SetBytes(reverseBytes(b))
+	v.Rsh(v, uint(offset%8))
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(k))
+	mask.Sub(mask, big.NewInt(1))
+	return v.And(v, mask)
+}
+
+// reverseBytes reverses a byte slice in place and returns it.
+func reverseBytes(b []byte) []byte {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
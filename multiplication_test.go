@@ -0,0 +1,201 @@
+package polyrat
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomPolynomial generates a polynomial of degree d with coefficients in [-m, m].
+func randomPolynomial(rng *rand.Rand, d int, m int64) *Polynomial {
+	poly, _ := NewPolynomial(d)
+	for i := 0; i < d; i++ {
+		poly.c[i] = rng.Int63n(2*m+1) - m
+	}
+	return poly
+}
+
+func TestMulSchoolbookKaratsuba(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, d := range []int{1, 2, 16, 64, 256, 2048} {
+		for _, m := range []int64{5, 1 << 20} {
+			a := randomPolynomial(rng, d, m)
+			b := randomPolynomial(rng, d, m)
+			// Reference product.
+			sb := mulSchoolbook(a.c, b.c)
+			// Karatsuba product.
+			kb := mulKaratsuba(a.c, b.c)
+			// Big integer product.
+			bb := mulKronecker(a.c, b.c)
+			for i := 0; i < d; i++ {
+				if sb[i] != kb[i] {
+					t.Fatalf("d = %d: expected Karatsuba coefficient %d at position %d but got %d", d, sb[i], i, kb[i])
+				}
+				if !bb[i].IsInt64() || sb[i] != bb[i].Int64() {
+					t.Fatalf("d = %d: expected big coefficient %d at position %d but got %s", d, sb[i], i, bb[i].String())
+				}
+			}
+		}
+	}
+}
+
+func TestMulLargeDegree(t *testing.T) {
+	// Schoolbook multiplication is too slow at d = 32768, so Karatsuba is checked against big integers.
+	rng := rand.New(rand.NewSource(2))
+	a := randomPolynomial(rng, 32768, 5)
+	b := randomPolynomial(rng, 32768, 5)
+	c, err := a.Mul(b)
+	if err != nil {
+		t.Error(err)
+	}
+	bb := mulKronecker(a.c, b.c)
+	for i := 0; i < len(bb); i++ {
+		if bb[i].Int64() != c.c[i] {
+			t.Fatalf("expected coefficient %s at position %d but got %d", bb[i].String(), i, c.c[i])
+		}
+	}
+}
+
+func TestMulNegacyclic(t *testing.T) {
+	// X^(d-1) x X = X^d = -1.
+	a, _ := NewPolynomial(16)
+	b, _ := NewPolynomial(16)
+	a.c[15] = 1
+	b.c[1] = 1
+	c, err := a.Mul(b)
+	if err != nil {
+		t.Error(err)
+	}
+	e, _ := NewPolynomial(16)
+	e.c[0] = -1
+	if !c.Equal(e) {
+		t.Errorf("expected %v but got %v", e.Coefficients(), c.Coefficients())
+	}
+}
+
+func TestMulBig(t *testing.T) {
+	// Coefficients whose products do not fit in 64-bit integers.
+	a, _ := NewPolynomial(4)
+	b, _ := NewPolynomial(4)
+	a.c[0], a.c[3] = math.MaxInt64, math.MinInt64
+	b.c[0], b.c[1] = 3, -2
+	c, err := a.MulBig(b)
+	if err != nil {
+		t.Error(err)
+	}
+	// Expected: 3(2^63 - 1) - 2^64 at X^0, -2(2^63 - 1) at X^1 and 3(-2^63) at X^3.
+	e := []string{"9223372036854775805", "-18446744073709551614", "0", "-27670116110564327424"}
+	for i := 0; i < len(e); i++ {
+		if c[i].String() != e[i] {
+			t.Errorf("expected coefficient %s at position %d but got %s", e[i], i, c[i].String())
+		}
+	}
+	// Check that Mul reports the overflow.
+	_, err = a.Mul(b)
+	if !errors.Is(err, ErrCoefficientOverflow) {
+		t.Errorf("expected error %v but got %v", ErrCoefficientOverflow, err)
+	}
+}
+
+func TestMulEncoded(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 5, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	// The product has twice as many fractional and integer digits.
+	scaled, err := NewParameters(-4, 10, 32)
+	if err != nil {
+		t.Error(err)
+	}
+	r := [][2]float64{{123.45, 6.78}, {123.45, -6.78}, {-0.5, -0.25}, {999.99, 999.99}}
+	for i := 0; i < len(r); i++ {
+		ca, err := Encode(r[i][0], params)
+		if err != nil {
+			t.Error(err)
+		}
+		cb, err := Encode(r[i][1], params)
+		if err != nil {
+			t.Error(err)
+		}
+		pa, _ := NewPolynomialFromCode(ca, params)
+		pb, _ := NewPolynomialFromCode(cb, params)
+		prod, err := pa.Mul(pb)
+		if err != nil {
+			t.Error(err)
+		}
+		// Decode the product with the scaled parameters.
+		dr, err := Decode(prod.Coefficients(), scaled)
+		if err != nil {
+			t.Error(err)
+		}
+		er := r[i][0] * r[i][1]
+		if math.Abs(dr-er) > 1e-9 {
+			t.Errorf("expected product %f but got %f", er, dr)
+		}
+	}
+}
+
+// BenchmarkProductGrowth compares the coefficient growth of products of the non-adjacent form
+// with the balanced and standard expansions of base 10, for the same rationals.
+// Every layout has the lower power -8, so the rationals k / 2^8 drawn from the message space
+// of the non-adjacent form, the smallest one, are encoded exactly by each of them.
+// The reported metrics are the greatest absolute coefficient of the products and the mean
+// number of nonzero digits of the factors.
+func BenchmarkProductGrowth(b *testing.B) {
+	layouts := []struct {
+		name   string
+		q      int
+		digits int
+	}{
+		{"naf", 23, DigitsNAF},
+		{"balanced", 7, DigitsBalanced},
+		{"standard", 7, DigitsStandard},
+	}
+	naf, err := NewParametersWithDigits(-8, 23, 64, DigitsNAF)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, layout := range layouts {
+		params, err := NewParametersWithDigits(-8, layout.q, 64, layout.digits)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(layout.name, func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			// Numerators are drawn from the smallest message space.
+			lb, _ := messageSpaceBounds(naf)
+			den := math.Pow(float64(naf.Base()), float64(-naf.MinPower()))
+			var growth, weight int64
+			for i := 0; i < b.N; i++ {
+				x := float64(rng.Int63n(-2*int64(lb))+int64(lb)) / den
+				y := float64(rng.Int63n(-2*int64(lb))+int64(lb)) / den
+				cx, err := Encode(x, params)
+				if err != nil {
+					b.Fatal(err)
+				}
+				cy, err := Encode(y, params)
+				if err != nil {
+					b.Fatal(err)
+				}
+				px, _ := NewPolynomialFromCode(cx, params)
+				py, _ := NewPolynomialFromCode(cy, params)
+				prod, err := px.Mul(py)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if m := int64(maxAbs(prod.c)); m > growth {
+					growth = m
+				}
+				for _, c := range append(cx, cy...) {
+					if c != 0 {
+						weight++
+					}
+				}
+			}
+			b.ReportMetric(float64(growth), "max-coeff")
+			b.ReportMetric(float64(weight)/float64(2*b.N), "weight/op")
+		})
+	}
+}
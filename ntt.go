@@ -0,0 +1,362 @@
+package polyrat
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// MaxNTTModulusBits is the greatest bit size of an NTT modulus.
+// Moduli are kept below 2^62 so that sums of residues do not overflow.
+const MaxNTTModulusBits = 62
+
+// NTT is a negacyclic number-theoretic transform of length d modulo an NTT-friendly prime q.
+// A prime is NTT-friendly when q = 1 mod 2d, so that a primitive 2d-th root of unity psi exists.
+// The forward transform evaluates a polynomial of Z_q[X]/(X^d + 1) at the odd powers of psi,
+// which turns negacyclic convolution into coefficient-wise multiplication.
+type NTT struct {
+	d         int      // d is the length of the transform.
+	q         uint64   // q is the prime modulus.
+	psiRev    []uint64 // psiRev are the powers of psi in bit-reversed order.
+	psiInvRev []uint64 // psiInvRev are the powers of the inverse of psi in bit-reversed order.
+	dInv      uint64   // dInv is the inverse of d modulo q.
+}
+
+// NewNTT creates a negacyclic number-theoretic transform of length d modulo the prime q.
+func NewNTT(d int, q uint64) (*NTT, error) {
+	// Validate length of the transform.
+	if d < 1 {
+		return nil, ErrDIsLessThanOne
+	}
+	if !isPowerOfTwo(d) {
+		return nil, ErrDIsNotAPowerOfTwo
+	}
+	// Validate modulus.
+	if bits.Len64(q) > MaxNTTModulusBits {
+		return nil, ErrNTTModulusIsTooLarge
+	}
+	if !new(big.Int).SetUint64(q).ProbablyPrime(20) {
+		return nil, ErrNTTModulusIsNotPrime
+	}
+	if q%uint64(2*d) != 1 {
+		return nil, ErrNTTModulusIsNotNTTFriendly
+	}
+	ntt := new(NTT)
+	ntt.d = d
+	ntt.q = q
+	// Primitive 2d-th root of unity and its inverse.
+	psi := primitiveRoot(q, uint64(2*d))
+	psiInv := powMod(psi, q-2, q)
+	// Powers in bit-reversed order.
+	logD := bits.Len(uint(d)) - 1
+	ntt.psiRev = make([]uint64, d)
+	ntt.psiInvRev = make([]uint64, d)
+	p, pInv := uint64(1), uint64(1)
+	for i := 0; i < d; i++ {
+		r := bitReverse(i, logD)
+		ntt.psiRev[r] = p
+		ntt.psiInvRev[r] = pInv
+		p = mulMod(p, psi, q)
+		pInv = mulMod(pInv, psiInv, q)
+	}
+	ntt.dInv = powMod(uint64(d), q-2, q)
+	return ntt, nil
+}
+
+// Getter for degree.
+func (ntt *NTT) Degree() int {
+	return ntt.d
+}
+
+// Getter for modulus.
+func (ntt *NTT) Modulus() uint64 {
+	return ntt.q
+}
+
+// Forward transforms, in place, the coefficients of a polynomial into the NTT domain.
+// The coefficients should be in [0, q) and the result is in bit-reversed order.
+func (ntt *NTT) Forward(a []uint64) error {
+	if len(a) != ntt.d {
+		return ErrNTTLengthIsDifferentFromDegree
+	}
+	q := ntt.q
+	// Cooley-Tukey butterflies with the powers of psi merged in.
+	t := ntt.d
+	for m := 1; m < ntt.d; m <<= 1 {
+		t >>= 1
+		for i := 0; i < m; i++ {
+			j1 := 2 * i * t
+			s := ntt.psiRev[m+i]
+			for j := j1; j < j1+t; j++ {
+				u := a[j]
+				v := mulMod(a[j+t], s, q)
+				a[j] = addMod(u, v, q)
+				a[j+t] = subMod(u, v, q)
+			}
+		}
+	}
+	return nil
+}
+
+// Inverse transforms, in place, a polynomial in the NTT domain back into its coefficients.
+func (ntt *NTT) Inverse(a []uint64) error {
+	if len(a) != ntt.d {
+		return ErrNTTLengthIsDifferentFromDegree
+	}
+	q := ntt.q
+	// Gentleman-Sande butterflies with the powers of the inverse of psi merged in.
+	t := 1
+	for m := ntt.d; m > 1; m >>= 1 {
+		j1 := 0
+		h := m / 2
+		for i := 0; i < h; i++ {
+			s := ntt.psiInvRev[h+i]
+			for j := j1; j < j1+t; j++ {
+				u := a[j]
+				v := a[j+t]
+				a[j] = addMod(u, v, q)
+				a[j+t] = mulMod(subMod(u, v, q), s, q)
+			}
+			j1 += 2 * t
+		}
+		t <<= 1
+	}
+	// Scale by the inverse of d.
+	for i := 0; i < ntt.d; i++ {
+		a[i] = mulMod(a[i], ntt.dInv, q)
+	}
+	return nil
+}
+
+// Mul computes, in place in a, the negacyclic product of two polynomials modulo q.
+// Both polynomials should have coefficients in [0, q).
+func (ntt *NTT) Mul(a, b []uint64) error {
+	if len(a) != ntt.d || len(b) != ntt.d {
+		return ErrNTTLengthIsDifferentFromDegree
+	}
+	// The second factor is copied so that it is not modified.
+	bb := make([]uint64, ntt.d)
+	copy(bb, b)
+	ntt.Forward(a)
+	ntt.Forward(bb)
+	for i := 0; i < ntt.d; i++ {
+		a[i] = mulMod(a[i], bb[i], ntt.q)
+	}
+	return ntt.Inverse(a)
+}
+
+// NTTMultiplier multiplies polynomials of Z[X]/(X^d + 1) with several NTT primes.
+// The residues of the product modulo each prime are combined with the Chinese
+// remainder theorem, so products are exact as long as every coefficient is
+// smaller than half of the product of the primes in absolute value.
+type NTTMultiplier struct {
+	d       int        // d is the degree of the ring.
+	ntts    []*NTT     // ntts are the transforms of each prime.
+	modulus *big.Int   // modulus is the product of the primes.
+	crt     []*big.Int // crt are the CRT basis elements (Q/q_i) x ((Q/q_i)^-1 mod q_i).
+}
+
+// NewNTTMultiplier creates a multiplier for the ring of degree d with the given NTT primes.
+func NewNTTMultiplier(d int, primes []uint64) (*NTTMultiplier, error) {
+	if len(primes) == 0 {
+		return nil, ErrNTTPrimesAreEmpty
+	}
+	mul := new(NTTMultiplier)
+	mul.d = d
+	mul.modulus = big.NewInt(1)
+	for _, q := range primes {
+		ntt, err := NewNTT(d, q)
+		if err != nil {
+			return nil, err
+		}
+		mul.ntts = append(mul.ntts, ntt)
+		mul.modulus.Mul(mul.modulus, new(big.Int).SetUint64(q))
+	}
+	// CRT basis.
+	for _, q := range primes {
+		bq := new(big.Int).SetUint64(q)
+		qi := new(big.Int).Quo(mul.modulus, bq)
+		inv := new(big.Int).ModInverse(qi, bq)
+		if inv == nil {
+			return nil, ErrNTTPrimesAreNotDistinct
+		}
+		mul.crt = append(mul.crt, inv.Mul(inv, qi))
+	}
+	return mul, nil
+}
+
+// GenerateNTTPrimes returns count distinct NTT-friendly primes for the ring of degree d,
+// in decreasing order, with at most bitSize bits. The bit size should be large enough
+// for 2d + 1, the smallest candidate.
+func GenerateNTTPrimes(d, bitSize, count int) ([]uint64, error) {
+	if d < 1 {
+		return nil, ErrDIsLessThanOne
+	}
+	if !isPowerOfTwo(d) {
+		return nil, ErrDIsNotAPowerOfTwo
+	}
+	if bitSize > MaxNTTModulusBits {
+		return nil, ErrNTTModulusIsTooLarge
+	}
+	if bitSize < bits.Len(uint(2*d+1)) {
+		return nil, ErrNTTBitSizeIsTooSmall
+	}
+	if count < 1 {
+		return nil, ErrNTTPrimeCountIsLessThanOne
+	}
+	var primes []uint64
+	// Candidates are 1 mod 2d, starting from the greatest one below 2^bitSize.
+	m := uint64(2 * d)
+	q := ((uint64(1)<<bitSize)-1)/m*m + 1
+	for ; q > m && len(primes) < count; q -= m {
+		if new(big.Int).SetUint64(q).ProbablyPrime(20) {
+			primes = append(primes, q)
+		}
+	}
+	if len(primes) < count {
+		return nil, ErrNTTPrimesAreNotEnough
+	}
+	return primes, nil
+}
+
+// Getter for degree.
+func (mul *NTTMultiplier) Degree() int {
+	return mul.d
+}
+
+// Modulus returns the product of the primes.
+func (mul *NTTMultiplier) Modulus() *big.Int {
+	return new(big.Int).Set(mul.modulus)
+}
+
+// Mul returns the exact negacyclic product of two polynomials.
+// An error is returned if the product can exceed the modulus.
+func (mul *NTTMultiplier) Mul(a, b []int64) ([]*big.Int, error) {
+	if len(a) != mul.d || len(b) != mul.d {
+		return nil, ErrNTTLengthIsDifferentFromDegree
+	}
+	// Worst case of a coefficient: d x max|a| x max|b|, which should be less than Q/2.
+	bound := new(big.Int).SetUint64(maxAbs(a))
+	bound.Mul(bound, new(big.Int).SetUint64(maxAbs(b)))
+	bound.Mul(bound, big.NewInt(int64(2*mul.d)))
+	if bound.Cmp(mul.modulus) >= 0 {
+		return nil, ErrNTTModulusIsTooSmall
+	}
+	// Product modulo each prime.
+	residues := make([][]uint64, len(mul.ntts))
+	for i, ntt := range mul.ntts {
+		ra := reduceCoefficients(a, ntt.q)
+		rb := reduceCoefficients(b, ntt.q)
+		ntt.Mul(ra, rb)
+		residues[i] = ra
+	}
+	// Chinese remainder theorem with centered lift.
+	half := new(big.Int).Rsh(mul.modulus, 1)
+	c := make([]*big.Int, mul.d)
+	t := new(big.Int)
+	for j := 0; j < mul.d; j++ {
+		x := new(big.Int)
+		for i := range mul.ntts {
+			t.SetUint64(residues[i][j])
+			t.Mul(t, mul.crt[i])
+			x.Add(x, t)
+		}
+		x.Mod(x, mul.modulus)
+		if x.Cmp(half) > 0 {
+			x.Sub(x, mul.modulus)
+		}
+		c[j] = x
+	}
+	return c, nil
+}
+
+// MulNTT returns the product of two polynomials computed with number-theoretic transforms.
+// An error is returned if a coefficient of the product does not fit in a 64-bit integer.
+func (poly *Polynomial) MulNTT(other *Polynomial, mul *NTTMultiplier) (*Polynomial, error) {
+	// Check that both polynomials belong to the ring of the multiplier.
+	if poly.d != other.d || poly.d != mul.d {
+		return nil, ErrPolynomialDegreesAreDifferent
+	}
+	c, err := mul.Mul(poly.c, other.c)
+	if err != nil {
+		return nil, err
+	}
+	prod := poly.zero()
+	for i := 0; i < poly.d; i++ {
+		if !c[i].IsInt64() {
+			return nil, ErrCoefficientOverflow
+		}
+		prod.c[i] = c[i].Int64()
+	}
+	return prod, nil
+}
+
+// reduceCoefficients maps signed coefficients into [0, q).
+func reduceCoefficients(a []int64, q uint64) []uint64 {
+	r := make([]uint64, len(a))
+	for i, v := range a {
+		if v >= 0 {
+			r[i] = uint64(v) % q
+		} else {
+			// The conversion of the negation handles the smallest 64-bit integer.
+			r[i] = subMod(0, uint64(-v)%q, q)
+		}
+	}
+	return r
+}
+
+// primitiveRoot finds a primitive n-th root of unity modulo the prime q, where n is a power of 2 dividing q - 1.
+func primitiveRoot(q, n uint64) uint64 {
+	for x := uint64(2); ; x++ {
+		// x^((q-1)/n) has an order dividing n.
+		r := powMod(x, (q-1)/n, q)
+		// The order is exactly n if r^(n/2) = -1.
+		if powMod(r, n/2, q) == q-1 {
+			return r
+		}
+	}
+}
+
+// bitReverse reverses the lower l bits of i.
+func bitReverse(i, l int) int {
+	if l == 0 {
+		return 0
+	}
+	return int(bits.Reverse64(uint64(i)) >> (64 - l))
+}
+
+// addMod computes (a + b) mod q for a, b in [0, q).
+func addMod(a, b, q uint64) uint64 {
+	s := a + b
+	if s >= q {
+		s -= q
+	}
+	return s
+}
+
+// subMod computes (a - b) mod q for a, b in [0, q).
+func subMod(a, b, q uint64) uint64 {
+	if a >= b {
+		return a - b
+	}
+	return a + q - b
+}
+
+// mulMod computes (a x b) mod q.
+func mulMod(a, b, q uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, q)
+}
+
+// powMod computes (a^e) mod q.
+func powMod(a, e, q uint64) uint64 {
+	r := uint64(1)
+	a %= q
+	for e > 0 {
+		if e&1 == 1 {
+			r = mulMod(r, a, q)
+		}
+		a = mulMod(a, a, q)
+		e >>= 1
+	}
+	return r
+}
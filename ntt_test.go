@@ -0,0 +1,182 @@
+package polyrat
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestNewNTT(t *testing.T) {
+	// 7681 = 15 x 512 + 1 is NTT-friendly for d <= 256.
+	_, err := NewNTT(256, 7681)
+	if err != nil {
+		t.Error(err)
+	}
+	// Check that an error is thrown when the modulus is not 1 mod 2d.
+	_, err = NewNTT(512, 7681)
+	if !errors.Is(err, ErrNTTModulusIsNotNTTFriendly) {
+		t.Errorf("expected error %v but got %v", ErrNTTModulusIsNotNTTFriendly, err)
+	}
+	// Check that an error is thrown when the modulus is not a prime.
+	_, err = NewNTT(4, 9)
+	if !errors.Is(err, ErrNTTModulusIsNotPrime) {
+		t.Errorf("expected error %v but got %v", ErrNTTModulusIsNotPrime, err)
+	}
+	// Check that an error is thrown when the length is not a power of 2.
+	_, err = NewNTT(3, 7681)
+	if !errors.Is(err, ErrDIsNotAPowerOfTwo) {
+		t.Errorf("expected error %v but got %v", ErrDIsNotAPowerOfTwo, err)
+	}
+}
+
+func TestNTTForwardInverse(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	primes, err := GenerateNTTPrimes(1024, 60, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ntt, err := NewNTT(1024, primes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := make([]uint64, 1024)
+	for i := range a {
+		a[i] = rng.Uint64() % ntt.Modulus()
+	}
+	b := make([]uint64, len(a))
+	copy(b, a)
+	// Check that the inverse undoes the forward transform.
+	ntt.Forward(b)
+	ntt.Inverse(b)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("expected coefficient %d at position %d but got %d", a[i], i, b[i])
+		}
+	}
+	// Check that the transform of X is the set of odd powers of psi.
+	x := make([]uint64, 1024)
+	x[1] = 1
+	ntt.Forward(x)
+	for i := range x {
+		if powMod(x[i], 2048, ntt.Modulus()) != 1 || powMod(x[i], 1024, ntt.Modulus()) != ntt.Modulus()-1 {
+			t.Fatalf("expected a primitive 2048-th root of unity at position %d but got %d", i, x[i])
+		}
+	}
+	// Check that an error is thrown when the length is different from the degree.
+	err = ntt.Forward(make([]uint64, 16))
+	if !errors.Is(err, ErrNTTLengthIsDifferentFromDegree) {
+		t.Errorf("expected error %v but got %v", ErrNTTLengthIsDifferentFromDegree, err)
+	}
+}
+
+func TestGenerateNTTPrimes(t *testing.T) {
+	primes, err := GenerateNTTPrimes(16384, 50, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, q := range primes {
+		if q >= 1<<50 || q%32768 != 1 {
+			t.Errorf("prime %d is not an NTT-friendly prime of 50 bits", q)
+		}
+		if i > 0 && q >= primes[i-1] {
+			t.Errorf("primes should be distinct and in decreasing order")
+		}
+	}
+	// Check that an error is thrown when there are not enough primes.
+	_, err = GenerateNTTPrimes(1024, 12, 2)
+	if !errors.Is(err, ErrNTTPrimesAreNotEnough) {
+		t.Errorf("expected error %v but got %v", ErrNTTPrimesAreNotEnough, err)
+	}
+	// Check that an error is thrown when the bit size is too small.
+	for _, bitSize := range []int{-1, 0, 11} {
+		_, err = GenerateNTTPrimes(1024, bitSize, 1)
+		if !errors.Is(err, ErrNTTBitSizeIsTooSmall) {
+			t.Errorf("expected error %v but got %v", ErrNTTBitSizeIsTooSmall, err)
+		}
+	}
+	// Check that an error is thrown when the count is less than one.
+	for _, count := range []int{-1, 0} {
+		_, err = GenerateNTTPrimes(1024, 40, count)
+		if !errors.Is(err, ErrNTTPrimeCountIsLessThanOne) {
+			t.Errorf("expected error %v but got %v", ErrNTTPrimeCountIsLessThanOne, err)
+		}
+	}
+}
+
+func TestNTTMultiplier(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	// A single prime.
+	primes, err := GenerateNTTPrimes(2048, 40, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mul, err := NewNTTMultiplier(2048, primes[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := randomPolynomial(rng, 2048, 5)
+	b := randomPolynomial(rng, 2048, 5)
+	c, err := a.MulNTT(b, mul)
+	if err != nil {
+		t.Error(err)
+	}
+	e, err := a.Mul(b)
+	if err != nil {
+		t.Error(err)
+	}
+	if !c.Equal(e) {
+		t.Error("NTT product is different from Karatsuba product")
+	}
+	// Coefficients of 30 bits need more than one 40-bit prime.
+	a = randomPolynomial(rng, 2048, 1<<30)
+	b = randomPolynomial(rng, 2048, 1<<30)
+	_, err = mul.Mul(a.c, b.c)
+	if !errors.Is(err, ErrNTTModulusIsTooSmall) {
+		t.Errorf("expected error %v but got %v", ErrNTTModulusIsTooSmall, err)
+	}
+	// Two primes combined with CRT.
+	mul, err = NewNTTMultiplier(2048, primes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc, err := mul.Mul(a.c, b.c)
+	if err != nil {
+		t.Error(err)
+	}
+	ec := mulKronecker(a.c, b.c)
+	for i := range ec {
+		if ec[i].Cmp(cc[i]) != 0 {
+			t.Fatalf("expected coefficient %s at position %d but got %s", ec[i].String(), i, cc[i].String())
+		}
+	}
+	// Check that an error is thrown when the primes are repeated.
+	_, err = NewNTTMultiplier(2048, []uint64{primes[0], primes[0]})
+	if !errors.Is(err, ErrNTTPrimesAreNotDistinct) {
+		t.Errorf("expected error %v but got %v", ErrNTTPrimesAreNotDistinct, err)
+	}
+}
+
+func TestNTTLargeDegree(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	primes, err := GenerateNTTPrimes(16384, 60, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mul, err := NewNTTMultiplier(16384, primes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := randomPolynomial(rng, 16384, 5)
+	b := randomPolynomial(rng, 16384, 5)
+	c, err := a.MulNTT(b, mul)
+	if err != nil {
+		t.Error(err)
+	}
+	e, err := a.Mul(b)
+	if err != nil {
+		t.Error(err)
+	}
+	if !c.Equal(e) {
+		t.Error("NTT product is different from Karatsuba product")
+	}
+}
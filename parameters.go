@@ -1,6 +1,21 @@
 package polyrat
 
-import "math"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Digit sets of the expansion.
+const (
+	DigitsBalanced = iota // DigitsBalanced uses digits in [-b/2, b/2).
+	DigitsStandard        // DigitsStandard uses digits in [0, b), all of them with the sign of the rational.
+	DigitsNAF             // DigitsNAF uses the non-adjacent form of base 2: digits in {-1, 0, 1} without adjacent nonzero digits.
+)
+
+// NAFBase is the base of the non-adjacent form.
+const NAFBase = 2
 
 // Parameters struct organizes the base, high power, low power
 // and polynomial degree information given to the encoding and
@@ -10,6 +25,10 @@ type Parameters struct {
 	q int // q is the higher power.
 	p int // p is the lower power.
 	d int // d is the degree of the polynomial.
+	// digits is the digit set of the expansion.
+	digits int
+	// denominator is the common denominator of the encoded rationals, 0 meaning 1.
+	denominator int64
 }
 
 // NewParameters creates a struct that validates all the parameters
@@ -31,6 +50,24 @@ func NewParameters(p, q, d int) (*Parameters, error) {
 	return params, nil
 }
 
+// NewParametersWithDigits creates parameters whose expansion uses the given digit set.
+// NewParameters uses the balanced digit set. The non-adjacent form sets the base to NAFBase.
+func NewParametersWithDigits(p, q, d, digits int) (*Parameters, error) {
+	params, err := NewParameters(p, q, d)
+	// The digit set is only set once it is known to be valid.
+	if digits < DigitsBalanced || digits > DigitsNAF {
+		return params, ErrDigitSetIsInvalid
+	}
+	params.digits = digits
+	if digits == DigitsNAF {
+		params.b = NAFBase
+	}
+	if err != nil {
+		return params, err
+	}
+	return params, nil
+}
+
 // Getter for base.
 func (params *Parameters) Base() int {
 	return params.b
@@ -51,6 +88,47 @@ func (params *Parameters) Degree() int {
 	return params.d
 }
 
+// WithDenominator returns a copy of the parameters with a common denominator D.
+// Codes then represent their value divided by D, so rationals whose denominator
+// divides D x b^|p| are encoded exactly, for instance 1/3 with D = 3.
+func (params *Parameters) WithDenominator(den int64) (*Parameters, error) {
+	if den < 1 {
+		return nil, ErrCommonDenominatorIsLessThanOne
+	}
+	p := *params
+	p.denominator = den
+	if den == 1 {
+		p.denominator = 0
+	}
+	return &p, nil
+}
+
+// Getter for common denominator.
+func (params *Parameters) Denominator() int64 {
+	if params.denominator == 0 {
+		return 1
+	}
+	return params.denominator
+}
+
+// Getter for digit set.
+func (params *Parameters) DigitSet() int {
+	return params.digits
+}
+
+// String returns a compact description of the parameters.
+// The digit set and the common denominator are only given when they are not the default ones.
+func (params *Parameters) String() string {
+	s := fmt.Sprintf("b=%d, p=%d, q=%d, d=%d", params.b, params.p, params.q, params.d)
+	if params.digits != DigitsBalanced {
+		s += ", digits=" + digitSetName(params.digits)
+	}
+	if params.denominator != 0 {
+		s += fmt.Sprintf(", denominator=%d", params.denominator)
+	}
+	return s
+}
+
 // validateP validates criteria for the smallest power of expansion.
 func (params *Parameters) validateP() error {
 	// p < q.
@@ -119,3 +197,113 @@ func (params *Parameters) validate() error {
 	}
 	return nil
 }
+
+// digitSetNames are the names of the digit sets in the JSON representation of the parameters.
+var digitSetNames = []string{
+	DigitsBalanced: "balanced",
+	DigitsStandard: "standard",
+	DigitsNAF:      "naf",
+}
+
+// digitSetName returns the name of a digit set, or its number when the digit set is unknown.
+func digitSetName(digits int) string {
+	if digits < DigitsBalanced || digits >= len(digitSetNames) {
+		return strconv.Itoa(digits)
+	}
+	return digitSetNames[digits]
+}
+
+// parametersJSON is the JSON representation of the parameters.
+type parametersJSON struct {
+	Base     int    `json:"base"`
+	MinPower int    `json:"p"`
+	MaxPower int    `json:"q"`
+	Degree   int    `json:"d"`
+	Digits   string `json:"digits,omitempty"`
+	Den      int64  `json:"denominator,omitempty"`
+}
+
+// MarshalJSON encodes the parameters as a JSON object with the base and the powers p, q and d.
+// The digit set and the common denominator are only given when they are not the default ones.
+func (params *Parameters) MarshalJSON() ([]byte, error) {
+	pj := parametersJSON{params.b, params.p, params.q, params.d, "", params.denominator}
+	if params.digits < DigitsBalanced || params.digits >= len(digitSetNames) {
+		return nil, ErrDigitSetIsInvalid
+	}
+	if params.digits != DigitsBalanced {
+		pj.Digits = digitSetNames[params.digits]
+	}
+	return json.Marshal(pj)
+}
+
+// UnmarshalJSON decodes and validates parameters encoded with MarshalJSON.
+func (params *Parameters) UnmarshalJSON(data []byte) error {
+	var pj parametersJSON
+	err := json.Unmarshal(data, &pj)
+	if err != nil {
+		return err
+	}
+	digits := DigitsBalanced
+	if pj.Digits != "" {
+		digits = -1
+		for set, name := range digitSetNames {
+			if pj.Digits == name {
+				digits = set
+			}
+		}
+		if digits < 0 {
+			return ErrDigitSetIsInvalid
+		}
+	}
+	if pj.Base != baseOfDigitSet(digits) {
+		return ErrBaseIsNotSupported
+	}
+	if pj.Degree > MaxDegree {
+		return ErrDIsGreaterThanMaxDegree
+	}
+	p, err := NewParametersWithDigits(pj.MinPower, pj.MaxPower, pj.Degree, digits)
+	if err != nil {
+		return err
+	}
+	if pj.Den != 0 {
+		p, err = p.WithDenominator(pj.Den)
+		if err != nil {
+			return err
+		}
+	}
+	*params = *p
+	return nil
+}
+
+// baseOfDigitSet returns the base used by a digit set.
+func baseOfDigitSet(digits int) int {
+	if digits == DigitsNAF {
+		return NAFBase
+	}
+	return Base
+}
+
+// SuggestParameters returns the smallest parameters that encode every rational whose absolute
+// value is at most maxAbs with the given number of decimal places. The lower power is -decimals,
+// the higher power is the smallest one whose message space contains -maxAbs and maxAbs, and the
+// degree is the smallest power of 2 greater than q + |p|.
+func SuggestParameters(maxAbs float64, decimals int) (*Parameters, error) {
+	if decimals < 1 {
+		return nil, ErrPIsGreaterThanOrEqualToZero
+	}
+	// Numerator of the greatest absolute value.
+	tmp := &Parameters{b: Base, p: -decimals}
+	n := parseRational(math.Abs(maxAbs), tmp)
+	// The message space of the numerators should fit in a 64-bit integer, that is, at most 18 digits.
+	for q := 1; q+decimals < 18; q++ {
+		tmp.q = q
+		if !inputIsInvalid(n, tmp) && !inputIsInvalid(-n, tmp) {
+			d := 1
+			for d <= q+decimals {
+				d *= 2
+			}
+			return NewParameters(-decimals, q, d)
+		}
+	}
+	return nil, ErrNumeratorIsNotInTheMessageSpaceRange
+}
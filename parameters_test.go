@@ -1,6 +1,8 @@
 package polyrat
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -74,4 +76,198 @@ func TestValidateD(t *testing.T) {
 			t.Error(ErrDIsLessThanOrEqualToQPlusP.Error())
 		}
 	}
+
+	// Check that degrees greater than the maximum degree are accepted by the constructor.
+	_, err = NewParameters(-4, 1, 2*MaxDegree)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParametersJSON(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-4, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ej := `{"base":10,"p":-4,"q":11,"d":16}`
+	if string(data) != ej {
+		t.Errorf("expected JSON %s but got %s", ej, string(data))
+	}
+	read := new(Parameters)
+	err = json.Unmarshal(data, read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *read != *params {
+		t.Errorf("expected parameters (%s) but got (%s)", params.String(), read.String())
+	}
+	// Check that an error is thrown when the base is not supported.
+	err = json.Unmarshal([]byte(`{"base":2,"p":-4,"q":11,"d":16}`), read)
+	if !errors.Is(err, ErrBaseIsNotSupported) {
+		t.Errorf("expected error %v but got %v", ErrBaseIsNotSupported, err)
+	}
+	// Check that an error is thrown when the degree is greater than the maximum degree.
+	err = json.Unmarshal([]byte(`{"base":10,"p":-4,"q":11,"d":131072}`), read)
+	if !errors.Is(err, ErrDIsGreaterThanMaxDegree) {
+		t.Errorf("expected error %v but got %v", ErrDIsGreaterThanMaxDegree, err)
+	}
+	// Check that the digit set is kept.
+	params, err = NewParametersWithDigits(-4, 11, 16, DigitsStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ej = `{"base":10,"p":-4,"q":11,"d":16,"digits":"standard"}`
+	if string(data) != ej {
+		t.Errorf("expected JSON %s but got %s", ej, string(data))
+	}
+	err = json.Unmarshal(data, read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *read != *params {
+		t.Errorf("expected parameters (%s) but got (%s)", params.String(), read.String())
+	}
+	// Check that the non-adjacent form has base 2.
+	err = json.Unmarshal([]byte(`{"base":2,"p":-4,"q":11,"d":16,"digits":"naf"}`), read)
+	if err != nil || read.Base() != NAFBase || read.DigitSet() != DigitsNAF {
+		t.Errorf("expected (b=2, p=-4, q=11, d=16, digits=naf) but got (%s) (%v)", read.String(), err)
+	}
+	err = json.Unmarshal([]byte(`{"base":10,"p":-4,"q":11,"d":16,"digits":"naf"}`), read)
+	if !errors.Is(err, ErrBaseIsNotSupported) {
+		t.Errorf("expected error %v but got %v", ErrBaseIsNotSupported, err)
+	}
+	// Check that an error is thrown when the digit set is unknown.
+	err = json.Unmarshal([]byte(`{"base":10,"p":-4,"q":11,"d":16,"digits":"binary"}`), read)
+	if !errors.Is(err, ErrDigitSetIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrDigitSetIsInvalid, err)
+	}
+}
+
+func TestWithDenominator(t *testing.T) {
+	params, err := NewParameters(-4, 11, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params3, err := params.WithDenominator(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The parameters are copied.
+	if params.Denominator() != 1 || params3.Denominator() != 3 {
+		t.Errorf("expected denominators 1 and 3 but got %d and %d", params.Denominator(), params3.Denominator())
+	}
+	es := "b=10, p=-4, q=11, d=16, denominator=3"
+	if params3.String() != es {
+		t.Errorf("expected %q but got %q", es, params3.String())
+	}
+	// The common denominator is kept in JSON.
+	data, err := json.Marshal(params3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ej := `{"base":10,"p":-4,"q":11,"d":16,"denominator":3}`
+	if string(data) != ej {
+		t.Errorf("expected JSON %s but got %s", ej, string(data))
+	}
+	read := new(Parameters)
+	err = json.Unmarshal(data, read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *read != *params3 {
+		t.Errorf("expected parameters (%s) but got (%s)", params3.String(), read.String())
+	}
+	// A denominator of 1 is the default one.
+	params1, _ := params3.WithDenominator(1)
+	if *params1 != *params {
+		t.Errorf("expected parameters (%s) but got (%s)", params.String(), params1.String())
+	}
+	// Check that an error is thrown when the denominator is less than 1.
+	_, err = params.WithDenominator(0)
+	if !errors.Is(err, ErrCommonDenominatorIsLessThanOne) {
+		t.Errorf("expected error %v but got %v", ErrCommonDenominatorIsLessThanOne, err)
+	}
+	err = json.Unmarshal([]byte(`{"base":10,"p":-4,"q":11,"d":16,"denominator":-3}`), read)
+	if !errors.Is(err, ErrCommonDenominatorIsLessThanOne) {
+		t.Errorf("expected error %v but got %v", ErrCommonDenominatorIsLessThanOne, err)
+	}
+}
+
+func TestNewParametersWithDigits(t *testing.T) {
+	params, err := NewParametersWithDigits(-4, 11, 16, DigitsStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.DigitSet() != DigitsStandard {
+		t.Errorf("expected digit set %d but got %d", DigitsStandard, params.DigitSet())
+	}
+	es := "b=10, p=-4, q=11, d=16, digits=standard"
+	if params.String() != es {
+		t.Errorf("expected %q but got %q", es, params.String())
+	}
+	// Check that an error is thrown when the digit set is unknown.
+	params, err = NewParametersWithDigits(-4, 11, 16, 7)
+	if !errors.Is(err, ErrDigitSetIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrDigitSetIsInvalid, err)
+	}
+	// The returned parameters keep a known digit set, so they can still be described.
+	es = "b=10, p=-4, q=11, d=16"
+	if params.String() != es {
+		t.Errorf("expected %q but got %q", es, params.String())
+	}
+	_, err = json.Marshal(params)
+	if err != nil {
+		t.Error(err)
+	}
+	// Unknown digit sets are described by their number.
+	params.digits = 7
+	es = "b=10, p=-4, q=11, d=16, digits=7"
+	if params.String() != es {
+		t.Errorf("expected %q but got %q", es, params.String())
+	}
+	_, err = json.Marshal(params)
+	if !errors.Is(err, ErrDigitSetIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrDigitSetIsInvalid, err)
+	}
+}
+
+func TestSuggestParameters(t *testing.T) {
+	// 98123.45 needs 5 integer digits, but 98123.45 > 44444.44, so q = 5.
+	params, err := SuggestParameters(98123.45, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.MinPower() != -2 || params.MaxPower() != 5 || params.Degree() != 8 {
+		t.Errorf("expected parameters (b=10, p=-2, q=5, d=8) but got (%s)", params.String())
+	}
+	_, err = Encode(98123.45, params)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = Encode(-98123.45, params)
+	if err != nil {
+		t.Error(err)
+	}
+	// 44444.44 fits with q = 4.
+	params, err = SuggestParameters(44444.44, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.MaxPower() != 4 {
+		t.Errorf("expected higher power %d but got %d", 4, params.MaxPower())
+	}
+	// Check that an error is thrown when there are no decimal places.
+	_, err = SuggestParameters(1.0, 0)
+	if !errors.Is(err, ErrPIsGreaterThanOrEqualToZero) {
+		t.Errorf("expected error %v but got %v", ErrPIsGreaterThanOrEqualToZero, err)
+	}
 }
@@ -0,0 +1,142 @@
+package polyrat
+
+// Polynomial is a plaintext polynomial in the negacyclic ring Z[X]/(X^d + 1).
+// Codes generated by Encode are elements of this ring, so operations on
+// polynomials model homomorphic operations on encrypted codes.
+type Polynomial struct {
+	d int     // d is the degree of the ring.
+	c []int64 // c are the coefficients, from X^0 to X^(d-1).
+}
+
+// NewPolynomial creates a zero polynomial in the ring of degree d.
+func NewPolynomial(d int) (*Polynomial, error) {
+	// Validate degree of the ring.
+	if d < 1 {
+		return nil, ErrDIsLessThanOne
+	}
+	if !isPowerOfTwo(d) {
+		return nil, ErrDIsNotAPowerOfTwo
+	}
+	poly := new(Polynomial)
+	poly.d = d
+	poly.c = make([]int64, d)
+	return poly, nil
+}
+
+// NewPolynomialFromCode creates a polynomial from a code generated with the given parameters.
+// The coefficients are copied, so the code can be reused.
+func NewPolynomialFromCode(code []int64, params *Parameters) (*Polynomial, error) {
+	// Validate degree of code.
+	err := validateDegreeOfCode(code, params)
+	if err != nil {
+		return nil, err
+	}
+	poly := new(Polynomial)
+	poly.d = len(code)
+	poly.c = make([]int64, len(code))
+	copy(poly.c, code)
+	return poly, nil
+}
+
+// Getter for degree.
+func (poly *Polynomial) Degree() int {
+	return poly.d
+}
+
+// Coefficients returns a copy of the coefficients, which can be decoded as a code.
+func (poly *Polynomial) Coefficients() []int64 {
+	c := make([]int64, poly.d)
+	copy(c, poly.c)
+	return c
+}
+
+// Add returns the sum of two polynomials.
+func (poly *Polynomial) Add(other *Polynomial) (*Polynomial, error) {
+	// Check that both polynomials belong to the same ring.
+	if poly.d != other.d {
+		return nil, ErrPolynomialDegreesAreDifferent
+	}
+	sum := poly.zero()
+	for i := 0; i < poly.d; i++ {
+		s, ok := addInt64(poly.c[i], other.c[i])
+		if !ok {
+			return nil, ErrCoefficientOverflow
+		}
+		sum.c[i] = s
+	}
+	return sum, nil
+}
+
+// Sub returns the difference of two polynomials.
+func (poly *Polynomial) Sub(other *Polynomial) (*Polynomial, error) {
+	// Negate the subtrahend.
+	neg, err := other.Neg()
+	if err != nil {
+		return nil, err
+	}
+	return poly.Add(neg)
+}
+
+// Neg returns the additive inverse of the polynomial.
+func (poly *Polynomial) Neg() (*Polynomial, error) {
+	return poly.MulScalar(-1)
+}
+
+// MulScalar returns the polynomial multiplied by an integer scalar.
+func (poly *Polynomial) MulScalar(k int64) (*Polynomial, error) {
+	prod := poly.zero()
+	for i := 0; i < poly.d; i++ {
+		m, ok := mulInt64(poly.c[i], k)
+		if !ok {
+			return nil, ErrCoefficientOverflow
+		}
+		prod.c[i] = m
+	}
+	return prod, nil
+}
+
+// Equal checks if two polynomials belong to the same ring and have the same coefficients.
+func (poly *Polynomial) Equal(other *Polynomial) bool {
+	if poly.d != other.d {
+		return false
+	}
+	for i := 0; i < poly.d; i++ {
+		if poly.c[i] != other.c[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// zero returns a zero polynomial in the same ring.
+func (poly *Polynomial) zero() *Polynomial {
+	z := new(Polynomial)
+	z.d = poly.d
+	z.c = make([]int64, poly.d)
+	return z
+}
+
+// MulMonomial returns the polynomial multiplied by the monomial X^k.
+// Since X^d = -1, coefficients that wrap around the degree are negated, and a
+// negative k divides the polynomial by X^|k|.
+func (poly *Polynomial) MulMonomial(k int) (*Polynomial, error) {
+	d := poly.d
+	// X^(2d) = 1, so k is reduced modulo 2d.
+	k = ((k % (2 * d)) + 2*d) % (2 * d)
+	rot := poly.zero()
+	for i := 0; i < d; i++ {
+		j := i + k
+		c := poly.c[i]
+		// Every wrap around the degree negates the coefficient.
+		for j >= d {
+			j -= d
+			m, ok := mulInt64(c, -1)
+			if !ok {
+				return nil, ErrCoefficientOverflow
+			}
+			c = m
+		}
+		rot.c[j] = c
+	}
+	return rot, nil
+}
@@ -0,0 +1,179 @@
+package polyrat
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewPolynomial(t *testing.T) {
+	// Check that a zero polynomial is created.
+	poly, err := NewPolynomial(16)
+	if err != nil {
+		t.Error(err)
+	}
+	if poly.Degree() != 16 {
+		t.Errorf("expected degree %d but got %d", 16, poly.Degree())
+	}
+	for i, c := range poly.Coefficients() {
+		if c != 0 {
+			t.Errorf("expected coefficient 0 at position %d but got %d", i, c)
+		}
+	}
+	// Check that an error is thrown when the degree is not a power of 2.
+	_, err = NewPolynomial(12)
+	if !errors.Is(err, ErrDIsNotAPowerOfTwo) {
+		t.Errorf("expected error %v but got %v", ErrDIsNotAPowerOfTwo, err)
+	}
+	// Check that an error is thrown when the degree of the code is different from the degree.
+	params, err := NewParameters(-4, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = NewPolynomialFromCode(make([]int64, 32), params)
+	if !errors.Is(err, ErrCodeDegreeIsDifferentFromDegree) {
+		t.Errorf("expected error %v but got %v", ErrCodeDegreeIsDifferentFromDegree, err)
+	}
+}
+
+func TestPolynomialAddSub(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Encode two rationals.
+	a, b := 123.45, -67.89
+	ca, err := Encode(a, params)
+	if err != nil {
+		t.Error(err)
+	}
+	cb, err := Encode(b, params)
+	if err != nil {
+		t.Error(err)
+	}
+	pa, err := NewPolynomialFromCode(ca, params)
+	if err != nil {
+		t.Error(err)
+	}
+	pb, err := NewPolynomialFromCode(cb, params)
+	if err != nil {
+		t.Error(err)
+	}
+	// Check that the sum decodes to a + b.
+	sum, err := pa.Add(pb)
+	if err != nil {
+		t.Error(err)
+	}
+	r, err := Decode(sum.Coefficients(), params)
+	if err != nil {
+		t.Error(err)
+	}
+	if r != 55.56 {
+		t.Errorf("expected sum %f but got %f", 55.56, r)
+	}
+	// Check that the difference decodes to a - b.
+	diff, err := pa.Sub(pb)
+	if err != nil {
+		t.Error(err)
+	}
+	r, err = Decode(diff.Coefficients(), params)
+	if err != nil {
+		t.Error(err)
+	}
+	if r != 191.34 {
+		t.Errorf("expected difference %f but got %f", 191.34, r)
+	}
+	// Check that subtracting the sum from itself gives zero.
+	zero, err := sum.Sub(sum)
+	if err != nil {
+		t.Error(err)
+	}
+	z, _ := NewPolynomial(16)
+	if !zero.Equal(z) {
+		t.Errorf("expected zero polynomial but got %v", zero.Coefficients())
+	}
+	// Check that polynomials of different degrees cannot be added.
+	other, _ := NewPolynomial(32)
+	_, err = pa.Add(other)
+	if !errors.Is(err, ErrPolynomialDegreesAreDifferent) {
+		t.Errorf("expected error %v but got %v", ErrPolynomialDegreesAreDifferent, err)
+	}
+}
+
+func TestPolynomialNegMulScalar(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-4, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	// Code of rational 98123.45.
+	c := []int64{4, 2, 1, -2, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 5, 5}
+	poly, err := NewPolynomialFromCode(c, params)
+	if err != nil {
+		t.Error(err)
+	}
+	// Check negation.
+	neg, err := poly.Neg()
+	if err != nil {
+		t.Error(err)
+	}
+	r, err := Decode(neg.Coefficients(), params)
+	if err != nil {
+		t.Error(err)
+	}
+	if r != -98123.45 {
+		t.Errorf("expected rational %f but got %f", -98123.45, r)
+	}
+	// Check multiplication by a scalar.
+	m, err := poly.MulScalar(3)
+	if err != nil {
+		t.Error(err)
+	}
+	r, err = Decode(m.Coefficients(), params)
+	if err != nil {
+		t.Error(err)
+	}
+	if r != 294370.35 {
+		t.Errorf("expected rational %f but got %f", 294370.35, r)
+	}
+	// Check that an overflow is detected.
+	_, err = m.MulScalar(math.MaxInt64)
+	if !errors.Is(err, ErrCoefficientOverflow) {
+		t.Errorf("expected error %v but got %v", ErrCoefficientOverflow, err)
+	}
+}
+
+func TestPolynomialMulMonomial(t *testing.T) {
+	// Polynomial 1 + 2X + 3X^3.
+	poly, _ := NewPolynomial(4)
+	poly.c[0], poly.c[1], poly.c[3] = 1, 2, 3
+	// Multiplication by X: X + 2X^2 + 3X^4 = -3 + X + 2X^2.
+	m, err := poly.MulMonomial(1)
+	if err != nil {
+		t.Error(err)
+	}
+	e := []int64{-3, 1, 2, 0}
+	for i := 0; i < len(e); i++ {
+		if m.c[i] != e[i] {
+			t.Errorf("expected coefficient %d at position %d but got %d", e[i], i, m.c[i])
+		}
+	}
+	// Division by X undoes the multiplication.
+	m, err = m.MulMonomial(-1)
+	if err != nil {
+		t.Error(err)
+	}
+	if !m.Equal(poly) {
+		t.Errorf("expected %v but got %v", poly.Coefficients(), m.Coefficients())
+	}
+	// Multiplication by X^d negates the polynomial.
+	m, err = poly.MulMonomial(4)
+	if err != nil {
+		t.Error(err)
+	}
+	neg, _ := poly.Neg()
+	if !m.Equal(neg) {
+		t.Errorf("expected %v but got %v", neg.Coefficients(), m.Coefficients())
+	}
+}
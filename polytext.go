@@ -0,0 +1,180 @@
+package polyrat
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Notations of the coefficients of a textual polynomial.
+const (
+	NotationDecimal = iota // NotationDecimal writes signed decimal coefficients, as in "5x^15 - 2x^3 + 1".
+	NotationHex            // NotationHex writes hexadecimal coefficients modulo t, as in "5x^15 + FFFFFFEx^3 + 1".
+)
+
+// PolynomialStyle describes the textual form of a polynomial.
+// The hexadecimal notation is the one of SEAL and OpenFHE plaintexts and needs the plaintext modulus.
+type PolynomialStyle struct {
+	Notation int    // Notation is the notation of the coefficients.
+	Modulus  uint64 // Modulus is the plaintext modulus t of the hexadecimal notation.
+}
+
+// FormatPolynomial writes a code as a polynomial with terms of decreasing powers,
+// leaving out the zero coefficients. The zero polynomial is written "0".
+func FormatPolynomial(code []int64, style PolynomialStyle) (string, error) {
+	if err := validatePolynomialStyle(style); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i := len(code) - 1; i >= 0; i-- {
+		c := code[i]
+		if c == 0 {
+			continue
+		}
+		var coeff string
+		switch style.Notation {
+		case NotationHex:
+			if sb.Len() > 0 {
+				sb.WriteString(" + ")
+			}
+			coeff = strings.ToUpper(strconv.FormatUint(reduceCoefficients([]int64{c}, style.Modulus)[0], 16))
+		case NotationDecimal:
+			// The magnitude of the minimum int64 is written as an unsigned value.
+			m := uint64(c)
+			if c < 0 {
+				m = -m
+			}
+			switch {
+			case sb.Len() == 0 && c < 0:
+				sb.WriteString("-")
+			case c < 0:
+				sb.WriteString(" - ")
+			case sb.Len() > 0:
+				sb.WriteString(" + ")
+			}
+			coeff = strconv.FormatUint(m, 10)
+		}
+		sb.WriteString(coeff)
+		if i > 0 {
+			sb.WriteString("x^")
+			sb.WriteString(strconv.Itoa(i))
+		}
+	}
+	if sb.Len() == 0 {
+		return "0", nil
+	}
+	return sb.String(), nil
+}
+
+// ParsePolynomial reads a polynomial written as in FormatPolynomial into a code of degree d.
+// Terms can be in any order, but each power should appear at most once and be less than d.
+// Hexadecimal coefficients should be less than t and are lifted to (-t/2, t/2].
+func ParsePolynomial(text string, d int, style PolynomialStyle) ([]int64, error) {
+	if d < 1 {
+		return nil, ErrDIsLessThanOne
+	}
+	if !isPowerOfTwo(d) {
+		return nil, ErrDIsNotAPowerOfTwo
+	}
+	if err := validatePolynomialStyle(style); err != nil {
+		return nil, err
+	}
+	s := strings.Join(strings.Fields(text), "")
+	if s == "" {
+		return nil, ErrPolynomialTextIsInvalid
+	}
+	code := make([]int64, d)
+	seen := make([]bool, d)
+	for i := 0; i < len(s); {
+		// Read the separator and the sign of the term.
+		negative := false
+		if i > 0 {
+			if s[i] != '+' && s[i] != '-' {
+				return nil, ErrPolynomialTextIsInvalid
+			}
+			negative = s[i] == '-'
+			i++
+		}
+		if style.Notation == NotationDecimal && i < len(s) && s[i] == '-' && (i == 0 || !negative) {
+			negative = true
+			i++
+		}
+		if negative && style.Notation == NotationHex {
+			return nil, ErrPolynomialTextIsInvalid
+		}
+		// Read the coefficient.
+		j := i
+		for j < len(s) && isCoefficientDigit(s[j], style.Notation) {
+			j++
+		}
+		if j == i {
+			return nil, ErrPolynomialTextIsInvalid
+		}
+		c, err := parseCoefficient(s[i:j], negative, style)
+		if err != nil {
+			return nil, err
+		}
+		i = j
+		// Read the power of the term.
+		k := 0
+		if strings.HasPrefix(s[i:], "x^") {
+			i += 2
+			j = i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			k, err = strconv.Atoi(s[i:j])
+			if err != nil {
+				return nil, ErrPolynomialTextIsInvalid
+			}
+			i = j
+		}
+		if k >= d || seen[k] {
+			return nil, ErrCodeIndexIsInvalid
+		}
+		seen[k] = true
+		code[k] = c
+	}
+	return code, nil
+}
+
+// validatePolynomialStyle checks the notation and, for the hexadecimal notation, the plaintext modulus.
+func validatePolynomialStyle(style PolynomialStyle) error {
+	switch style.Notation {
+	case NotationDecimal:
+		return nil
+	case NotationHex:
+		if style.Modulus < 2 || bits.Len64(style.Modulus) > MaxNTTModulusBits {
+			return ErrPlaintextModulusIsInvalid
+		}
+		return nil
+	}
+	return ErrPolynomialNotationIsInvalid
+}
+
+// isCoefficientDigit reports whether a character is a digit of a coefficient in the given notation.
+func isCoefficientDigit(ch byte, notation int) bool {
+	if ch >= '0' && ch <= '9' {
+		return true
+	}
+	return notation == NotationHex && (ch >= 'A' && ch <= 'F' || ch >= 'a' && ch <= 'f')
+}
+
+// parseCoefficient reads the digits of a coefficient.
+func parseCoefficient(digits string, negative bool, style PolynomialStyle) (int64, error) {
+	if style.Notation == NotationHex {
+		v, err := strconv.ParseUint(digits, 16, 64)
+		if err != nil || v >= style.Modulus {
+			return 0, ErrPolynomialTextIsInvalid
+		}
+		return centeredLift([]uint64{v}, style.Modulus)[0], nil
+	}
+	if negative {
+		digits = "-" + digits
+	}
+	v, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, ErrPolynomialTextIsInvalid
+	}
+	return v, nil
+}
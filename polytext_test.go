@@ -0,0 +1,100 @@
+package polyrat
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatPolynomial(t *testing.T) {
+	code := []int64{1, 0, 0, -2, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 5, 5}
+	tests := []struct {
+		style    PolynomialStyle
+		expected string
+	}{
+		{PolynomialStyle{Notation: NotationHex, Modulus: 0xFFFFFFF}, "5x^15 + 5x^14 + 1x^5 + FFFFFFDx^3 + 1"},
+		{PolynomialStyle{Notation: NotationDecimal}, "5x^15 + 5x^14 + 1x^5 - 2x^3 + 1"},
+	}
+	for _, test := range tests {
+		s, err := FormatPolynomial(code, test.style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != test.expected {
+			t.Errorf("expected %q but got %q", test.expected, s)
+		}
+		c, err := ParsePolynomial(s, len(code), test.style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range code {
+			if c[i] != code[i] {
+				t.Fatalf("expected %v but got %v", code, c)
+			}
+		}
+	}
+	// The zero polynomial and a leading negative coefficient.
+	s, _ := FormatPolynomial(make([]int64, 4), PolynomialStyle{})
+	if s != "0" {
+		t.Errorf("expected %q but got %q", "0", s)
+	}
+	s, _ = FormatPolynomial([]int64{0, 0, -3, 0}, PolynomialStyle{})
+	if s != "-3x^2" {
+		t.Errorf("expected %q but got %q", "-3x^2", s)
+	}
+}
+
+func TestParsePolynomial(t *testing.T) {
+	hex := PolynomialStyle{Notation: NotationHex, Modulus: 65537}
+	tests := []struct {
+		text     string
+		style    PolynomialStyle
+		expected []int64
+	}{
+		{"FFFFx^3 + 10000x^2 + 1x^1 + 2", hex, []int64{2, 1, -1, -2}},
+		{"2 + fffe x^1", hex, []int64{2, -3, 0, 0}},
+		{"0", hex, []int64{0, 0, 0, 0}},
+		{"-5x^3+-4x^2 - 3x^1 + 2", PolynomialStyle{}, []int64{2, -3, -4, -5}},
+	}
+	for _, test := range tests {
+		c, err := ParsePolynomial(test.text, 4, test.style)
+		if err != nil {
+			t.Fatalf("%q: %v", test.text, err)
+		}
+		for i := range test.expected {
+			if c[i] != test.expected[i] {
+				t.Fatalf("%q: expected %v but got %v", test.text, test.expected, c)
+			}
+		}
+	}
+}
+
+func TestParsePolynomialErrors(t *testing.T) {
+	hex := PolynomialStyle{Notation: NotationHex, Modulus: 65537}
+	tests := []struct {
+		text  string
+		d     int
+		style PolynomialStyle
+		err   error
+	}{
+		{"1x^2", 3, PolynomialStyle{}, ErrDIsNotAPowerOfTwo},
+		{"1x^2", 4, PolynomialStyle{Notation: 2}, ErrPolynomialNotationIsInvalid},
+		{"1x^2", 4, PolynomialStyle{Notation: NotationHex}, ErrPlaintextModulusIsInvalid},
+		{"", 4, PolynomialStyle{}, ErrPolynomialTextIsInvalid},
+		{"x^2", 4, PolynomialStyle{}, ErrPolynomialTextIsInvalid},
+		{"1x^2 + ", 4, PolynomialStyle{}, ErrPolynomialTextIsInvalid},
+		{"1x^2 - -1", 4, PolynomialStyle{}, ErrPolynomialTextIsInvalid},
+		{"1x^2 * 1", 4, PolynomialStyle{}, ErrPolynomialTextIsInvalid},
+		{"Ax^2", 4, PolynomialStyle{}, ErrPolynomialTextIsInvalid},
+		{"99999999999999999999", 4, PolynomialStyle{}, ErrPolynomialTextIsInvalid},
+		{"10001x^2", 4, hex, ErrPolynomialTextIsInvalid},
+		{"1x^2 - 1", 4, hex, ErrPolynomialTextIsInvalid},
+		{"1x^4", 4, PolynomialStyle{}, ErrCodeIndexIsInvalid},
+		{"1x^2 + 1x^2", 4, PolynomialStyle{}, ErrCodeIndexIsInvalid},
+	}
+	for _, test := range tests {
+		_, err := ParsePolynomial(test.text, test.d, test.style)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: expected error %v but got %v", test.text, test.err, err)
+		}
+	}
+}
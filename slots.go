@@ -0,0 +1,69 @@
+package polyrat
+
+import (
+	"math/big"
+)
+
+// Packed codes hold several values in disjoint windows of slotWidth coefficients.
+// The slot k occupies the coefficients from k x slotWidth to (k+1) x slotWidth - 1,
+// where its coefficient j is the digit of the power j + p, so that the q + |p| + 1
+// digits of a value are followed by guard digits. Additions of packed codes add the
+// values slot-wise, and the guard digits keep the digits that grow beyond the highest
+// power, for instance after a multiplication by X, inside their slot.
+
+// validateSlots checks that the slots have guard digits and fit in the degree.
+func validateSlots(count, slotWidth int, params *Parameters) error {
+	if slotWidth <= polynomialLength(params) {
+		return ErrSlotWidthIsTooSmall
+	}
+	if count*slotWidth > params.Degree() {
+		return ErrSlotsDoNotFitInTheDegree
+	}
+	return nil
+}
+
+// EncodePacked encodes several rational numbers into the slots of one set of polynomial degrees.
+// Each rational is truncated as in Encode and should be in the message space.
+func EncodePacked(rats []float64, params *Parameters, slotWidth int) ([]int64, error) {
+	err := validateSlots(len(rats), slotWidth, params)
+	if err != nil {
+		return nil, err
+	}
+	code := make([]int64, params.Degree())
+	for k, rat := range rats {
+		// Transforms a rational number into an integer in the message space.
+		n, err := encodeNumerator(rat, params)
+		if err != nil {
+			return nil, err
+		}
+		// The expansion starts with the digit of the power p.
+		copy(code[k*slotWidth:], expansion(n, params))
+	}
+	return code, nil
+}
+
+// DecodePacked decodes the d / slotWidth slots of a packed code into their rationals.
+// Every coefficient of a slot is decoded, guard digits included.
+func DecodePacked(code []int64, params *Parameters, slotWidth int) ([]float64, error) {
+	// Validate input.
+	err := validateDecodingParameters(code, params)
+	if err == nil {
+		err = validateSlots(1, slotWidth, params)
+	}
+	if err != nil {
+		return nil, newDecodeError(code, params, err)
+	}
+	b := big.NewInt(int64(params.Base()))
+	den := codeDenominator(params)
+	rats := make([]float64, params.Degree()/slotWidth)
+	for k := range rats {
+		// Numerator of the slot, from its highest digit.
+		n := new(big.Int)
+		for j := slotWidth - 1; j >= 0; j-- {
+			n.Mul(n, b)
+			n.Add(n, big.NewInt(code[k*slotWidth+j]))
+		}
+		rats[k] = fractionFloat(new(big.Rat).SetFrac(n, den), params)
+	}
+	return rats, nil
+}
@@ -0,0 +1,122 @@
+package polyrat
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEncodePacked(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Slots of 6 digits and 2 guard digits.
+	c, err := EncodePacked([]float64{12.34, -0.05}, params, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec := make([]int64, 32)
+	copy(ec, []int64{4, 3, 2, 1, 0, 0, 0, 0, -5, 0, 0, 0, 0, 0, 0, 0})
+	for i := range ec {
+		if c[i] != ec[i] {
+			t.Fatalf("expected code %v but got %v", ec, c)
+		}
+	}
+	// The remaining slots are zero.
+	r, err := DecodePacked(c, params, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	er := []float64{12.34, -0.05, 0, 0}
+	if len(r) != len(er) {
+		t.Fatalf("expected %d slots but got %d", len(er), len(r))
+	}
+	for i := range er {
+		if r[i] != er[i] {
+			t.Errorf("expected %v but got %v", er, r)
+			break
+		}
+	}
+}
+
+func TestPackedAdd(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := []float64{12.34, -56.78, 4444.25, -5555.55, 0.01, 0}
+	b := []float64{-12.34, -4444.25, 4444.25, 1, 0.99, -0.5}
+	ca, err := EncodePacked(a, params, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := EncodePacked(b, params, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pa, _ := NewPolynomialFromCode(ca, params)
+	pb, _ := NewPolynomialFromCode(cb, params)
+	sum, err := pa.Add(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := DecodePacked(sum.Coefficients(), params, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The sums are slot-wise, even outside of the message space.
+	for i := range a {
+		if er := a[i] + b[i]; r[i] != round(er, params) {
+			t.Errorf("slot %d: expected %v but got %v", i, er, r[i])
+		}
+	}
+	// Multiplying by X multiplies every slot by b, using a guard digit.
+	x, _ := NewPolynomial(64)
+	x.c[1] = 1
+	prod, err := pa.Mul(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = DecodePacked(prod.Coefficients(), params, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range a {
+		if er := 10 * a[i]; r[i] != round(er, params) {
+			t.Errorf("slot %d: expected %v but got %v", i, er, r[i])
+		}
+	}
+}
+
+func TestPackedErrors(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		rats      []float64
+		slotWidth int
+		err       error
+	}{
+		{[]float64{1}, 6, ErrSlotWidthIsTooSmall},
+		{[]float64{1, 2, 3, 4, 5}, 7, ErrSlotsDoNotFitInTheDegree},
+		{[]float64{1, 5000}, 8, ErrNumeratorIsNotInTheMessageSpaceRange},
+	}
+	for _, test := range tests {
+		_, err := EncodePacked(test.rats, params, test.slotWidth)
+		if !errors.Is(err, test.err) {
+			t.Errorf("expected error %v but got %v", test.err, err)
+		}
+	}
+	_, err = DecodePacked(make([]int64, 32), params, 4)
+	if !errors.Is(err, ErrSlotWidthIsTooSmall) {
+		t.Errorf("expected error %v but got %v", ErrSlotWidthIsTooSmall, err)
+	}
+	_, err = DecodePacked(make([]int64, 16), params, 8)
+	if !errors.Is(err, ErrCodeDegreeIsDifferentFromDegree) {
+		t.Errorf("expected error %v but got %v", ErrCodeDegreeIsDifferentFromDegree, err)
+	}
+}
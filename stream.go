@@ -0,0 +1,323 @@
+package polyrat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Record formats of a stream.
+const (
+	FormatCSV    = iota // FormatCSV is a CSV stream whose first record is the header.
+	FormatNDJSON        // FormatNDJSON is a stream of JSON objects, one per line.
+)
+
+// StreamOptions configure the encoding and decoding of streams.
+type StreamOptions struct {
+	Params    *Parameters // Params are used to encode the values, codes carry their own parameters.
+	Format    int         // Format is the record format of the input and the output.
+	Columns   []string    // Columns are the CSV columns or the NDJSON fields to encode or decode.
+	Container bool        // Container writes codes as base64 wire containers instead of JSON codes.
+	Encoding  int         // Encoding is the coefficient encoding of the containers.
+}
+
+// RowError describes a record that could not be encoded or decoded.
+type RowError struct {
+	Row    int    // Row is the number of the record, starting from 1 after the CSV header.
+	Column string // Column is the column or field that caused the error, empty if the record is malformed.
+	Err    error  // Err is the underlying error.
+}
+
+// Error returns the description of the row error.
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("polyrat: row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("polyrat: row %d, column %q: %v", e.Row, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// StreamResult summarizes a processed stream.
+type StreamResult struct {
+	Rows    int         // Rows is the number of records read.
+	Written int         // Written is the number of records written.
+	Errors  []*RowError // Errors are the records that were not written.
+}
+
+// EncodeStream reads records from r, encodes the selected columns and writes the records to w.
+// Records with an error are reported in the result and left out of the output, without
+// stopping the stream. An error is only returned when the stream itself cannot be processed.
+func EncodeStream(r io.Reader, w io.Writer, opts StreamOptions) (*StreamResult, error) {
+	if opts.Params == nil {
+		return nil, ErrParametersAreMissing
+	}
+	return processStream(r, w, opts, func(v interface{}) (interface{}, error) {
+		rat, err := parseStreamNumber(v)
+		if err != nil {
+			return nil, err
+		}
+		code, err := EncodeCode(rat, opts.Params)
+		if err != nil {
+			return nil, err
+		}
+		return formatStreamCode(code, opts)
+	})
+}
+
+// DecodeStream reads records from r, decodes the codes of the selected columns and writes the records to w.
+// Codes can be JSON codes or base64 wire containers. Records with an error are reported in the
+// result and left out of the output, without stopping the stream.
+func DecodeStream(r io.Reader, w io.Writer, opts StreamOptions) (*StreamResult, error) {
+	return processStream(r, w, opts, func(v interface{}) (interface{}, error) {
+		code, err := parseStreamCode(v)
+		if err != nil {
+			return nil, err
+		}
+		rat, err := code.Decode()
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatFloat(rat, 'f', -1, 64)), nil
+	})
+}
+
+// streamTransform converts the value of a selected column.
+type streamTransform func(v interface{}) (interface{}, error)
+
+// processStream applies a transformation to the selected columns of every record.
+func processStream(r io.Reader, w io.Writer, opts StreamOptions, transform streamTransform) (*StreamResult, error) {
+	if len(opts.Columns) == 0 {
+		return nil, ErrStreamColumnsAreEmpty
+	}
+	switch opts.Format {
+	case FormatCSV:
+		return processCSV(r, w, opts, transform)
+	case FormatNDJSON:
+		return processNDJSON(r, w, opts, transform)
+	}
+	return nil, ErrStreamFormatIsInvalid
+}
+
+// processCSV processes a CSV stream. Transformed JSON values are written as JSON text.
+func processCSV(r io.Reader, w io.Writer, opts StreamOptions, transform streamTransform) (*StreamResult, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cw := csv.NewWriter(w)
+	// Header.
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, c := range opts.Columns {
+		i := indexOf(header, c)
+		if i < 0 {
+			return nil, ErrStreamColumnIsMissing
+		}
+		indices = append(indices, i)
+	}
+	err = cw.Write(header)
+	if err != nil {
+		return nil, err
+	}
+	res := new(StreamResult)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		res.Rows++
+		if err != nil {
+			// Malformed records are reported, other errors stop the stream.
+			if _, ok := err.(*csv.ParseError); !ok {
+				return res, err
+			}
+			res.Errors = append(res.Errors, &RowError{Row: res.Rows, Err: err})
+			continue
+		}
+		if len(record) != len(header) {
+			res.Errors = append(res.Errors, &RowError{Row: res.Rows, Err: ErrStreamRecordIsInvalid})
+			continue
+		}
+		ok := true
+		for k, i := range indices {
+			v, err := transform(record[i])
+			if err != nil {
+				res.Errors = append(res.Errors, &RowError{Row: res.Rows, Column: opts.Columns[k], Err: err})
+				ok = false
+				break
+			}
+			record[i], err = csvCell(v)
+			if err != nil {
+				return res, err
+			}
+		}
+		if !ok {
+			continue
+		}
+		err = cw.Write(record)
+		if err != nil {
+			return res, err
+		}
+		res.Written++
+	}
+	cw.Flush()
+	return res, cw.Error()
+}
+
+// processNDJSON processes an NDJSON stream.
+func processNDJSON(r io.Reader, w io.Writer, opts StreamOptions, transform streamTransform) (*StreamResult, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	bw := bufio.NewWriter(w)
+	res := new(StreamResult)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		res.Rows++
+		var record map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		err := dec.Decode(&record)
+		if err != nil || record == nil {
+			res.Errors = append(res.Errors, &RowError{Row: res.Rows, Err: ErrStreamRecordIsInvalid})
+			continue
+		}
+		ok := true
+		for _, c := range opts.Columns {
+			v, found := record[c]
+			if !found {
+				res.Errors = append(res.Errors, &RowError{Row: res.Rows, Column: c, Err: ErrStreamColumnIsMissing})
+				ok = false
+				break
+			}
+			record[c], err = transform(v)
+			if err != nil {
+				res.Errors = append(res.Errors, &RowError{Row: res.Rows, Column: c, Err: err})
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return res, err
+		}
+		bw.Write(data)
+		bw.WriteByte('\n')
+		res.Written++
+	}
+	if err := scanner.Err(); err != nil {
+		return res, err
+	}
+	return res, bw.Flush()
+}
+
+// parseStreamNumber parses a number given as a CSV cell or as a JSON number.
+func parseStreamNumber(v interface{}) (float64, error) {
+	var s string
+	switch x := v.(type) {
+	case string:
+		s = strings.TrimSpace(x)
+	case json.Number:
+		s = x.String()
+	default:
+		return 0, ErrStreamValueIsInvalid
+	}
+	rat, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, ErrStreamValueIsInvalid
+	}
+	return rat, nil
+}
+
+// formatStreamCode formats a code as a JSON code or as a base64 wire container.
+func formatStreamCode(code *Code, opts StreamOptions) (interface{}, error) {
+	if !opts.Container {
+		return code, nil
+	}
+	container, err := NewContainer(code.Coefficients, code.Params, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = container.Write(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// parseStreamCode parses a code given as a JSON code or as a base64 wire container.
+// In CSV streams, JSON codes are given as JSON text.
+func parseStreamCode(v interface{}) (*Code, error) {
+	var data []byte
+	switch x := v.(type) {
+	case string:
+		s := strings.TrimSpace(x)
+		if !strings.HasPrefix(s, "{") {
+			// Base64 wire container.
+			raw, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, ErrStreamValueIsInvalid
+			}
+			container := new(Container)
+			err = container.Read(bytes.NewReader(raw))
+			if err != nil {
+				return nil, err
+			}
+			return NewCode(container.Code, container.Params)
+		}
+		data = []byte(s)
+	case map[string]interface{}:
+		var err error
+		data, err = json.Marshal(x)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrStreamValueIsInvalid
+	}
+	code := new(Code)
+	err := json.Unmarshal(data, code)
+	if err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// csvCell formats a transformed value as a CSV cell.
+func csvCell(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case json.Number:
+		return x.String(), nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// indexOf returns the index of a string in a slice, or -1 if it is not found.
+func indexOf(s []string, v string) int {
+	for i := range s {
+		if s[i] == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,129 @@
+package polyrat
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncodeStreamCSV(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 8)
+	if err != nil {
+		t.Error(err)
+	}
+	in := "id,amount,rate\n1,12.5,0.25\n2,4551.92,0.5\n3,abc,1\n4,-1.01,-0.75\n"
+	var out bytes.Buffer
+	opts := StreamOptions{Params: params, Format: FormatCSV, Columns: []string{"amount", "rate"}, Container: true, Encoding: EncodingPacked}
+	res, err := EncodeStream(strings.NewReader(in), &out, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rows 2 and 3 are reported without stopping the stream.
+	if res.Rows != 4 || res.Written != 2 || len(res.Errors) != 2 {
+		t.Fatalf("expected 4 rows, 2 written and 2 errors but got %d, %d and %d", res.Rows, res.Written, len(res.Errors))
+	}
+	if res.Errors[0].Row != 2 || res.Errors[0].Column != "amount" || !errors.Is(res.Errors[0], ErrNumeratorIsNotInTheMessageSpaceRange) {
+		t.Errorf("expected message space error at row 2 but got %v", res.Errors[0])
+	}
+	if res.Errors[1].Row != 3 || !errors.Is(res.Errors[1], ErrStreamValueIsInvalid) {
+		t.Errorf("expected invalid value error at row 3 but got %v", res.Errors[1])
+	}
+	// Decode the stream back.
+	var back bytes.Buffer
+	res, err = DecodeStream(&out, &back, StreamOptions{Format: FormatCSV, Columns: []string{"amount", "rate"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Errors) != 0 {
+		t.Fatalf("expected no errors but got %v", res.Errors)
+	}
+	e := "id,amount,rate\n1,12.5,0.25\n4,-1.01,-0.75\n"
+	if back.String() != e {
+		t.Errorf("expected %q but got %q", e, back.String())
+	}
+}
+
+func TestEncodeStreamContainerDenominator(t *testing.T) {
+	// Create parameters (p, q, d) with a common denominator.
+	params, err := NewParameters(-2, 3, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err = params.WithDenominator(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := "id,amount\n1,12.5\n2,-1.01\n"
+	var out bytes.Buffer
+	opts := StreamOptions{Params: params, Format: FormatCSV, Columns: []string{"amount"}, Container: true, Encoding: EncodingDense}
+	res, err := EncodeStream(strings.NewReader(in), &out, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Errors) != 0 {
+		t.Fatalf("expected no errors but got %v", res.Errors)
+	}
+	// The containers carry the common denominator, so the stream is decoded without the parameters.
+	var back bytes.Buffer
+	res, err = DecodeStream(&out, &back, StreamOptions{Format: FormatCSV, Columns: []string{"amount"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Errors) != 0 || back.String() != in {
+		t.Errorf("expected %q but got %q (errors %v)", in, back.String(), res.Errors)
+	}
+}
+
+func TestEncodeStreamNDJSON(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 8)
+	if err != nil {
+		t.Error(err)
+	}
+	in := "{\"id\":\"a\",\"x\":1.5}\nnot json\n{\"id\":\"b\"}\n{\"id\":\"c\",\"x\":-2.25}\n"
+	var out bytes.Buffer
+	res, err := EncodeStream(strings.NewReader(in), &out, StreamOptions{Params: params, Format: FormatNDJSON, Columns: []string{"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rows != 4 || res.Written != 2 || len(res.Errors) != 2 {
+		t.Fatalf("expected 4 rows, 2 written and 2 errors but got %d, %d and %d", res.Rows, res.Written, len(res.Errors))
+	}
+	if !errors.Is(res.Errors[0], ErrStreamRecordIsInvalid) || !errors.Is(res.Errors[1], ErrStreamColumnIsMissing) {
+		t.Errorf("expected malformed record and missing column errors but got %v", res.Errors)
+	}
+	// Codes are written as sparse JSON codes.
+	first := strings.SplitN(out.String(), "\n", 2)[0]
+	if !strings.Contains(first, `"parameters":{"base":10,"p":-2,"q":3,"d":8}`) {
+		t.Errorf("expected a JSON code but got %s", first)
+	}
+	var back bytes.Buffer
+	res, err = DecodeStream(&out, &back, StreamOptions{Format: FormatNDJSON, Columns: []string{"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eb := "{\"id\":\"a\",\"x\":1.5}\n{\"id\":\"c\",\"x\":-2.25}\n"
+	if len(res.Errors) != 0 || back.String() != eb {
+		t.Errorf("expected %q but got %q (errors %v)", eb, back.String(), res.Errors)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-2, 3, 8)
+	if err != nil {
+		t.Error(err)
+	}
+	// Check that an error is thrown when a column is not in the CSV header.
+	_, err = EncodeStream(strings.NewReader("a,b\n1,2\n"), new(bytes.Buffer), StreamOptions{Params: params, Format: FormatCSV, Columns: []string{"c"}})
+	if !errors.Is(err, ErrStreamColumnIsMissing) {
+		t.Errorf("expected error %v but got %v", ErrStreamColumnIsMissing, err)
+	}
+	// Check that an error is thrown when the format is invalid.
+	_, err = EncodeStream(strings.NewReader(""), new(bytes.Buffer), StreamOptions{Params: params, Format: 7, Columns: []string{"c"}})
+	if !errors.Is(err, ErrStreamFormatIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrStreamFormatIsInvalid, err)
+	}
+}
@@ -5,13 +5,15 @@ import (
 	"math/big"
 )
 
-func symmetricModulo(n int64) int64 {
-	// Base.
-	b := int64(Base)
-	// Remainder.
+func symmetricModulo(n, b int64) int64 {
+	// Remainder in [0, b).
+	// Go truncates the division, so negative numbers give negative remainders.
 	r := n % b
-	// This condition is the inverse of 0 <= r && r < m/2.
-	if r < 0 || b/2 <= r {
+	if r < 0 {
+		r += b
+	}
+	// Remainder in [-b/2, b/2).
+	if b/2 <= r {
 		r -= b
 	}
 	return r
@@ -22,26 +24,55 @@ func polynomialLength(params *Parameters) int {
 }
 
 func expansion(numerator int64, params *Parameters) []int64 {
+	switch params.DigitSet() {
+	case DigitsStandard:
+		return standardExpansion(numerator, params)
+	case DigitsNAF:
+		return nafExpansion(numerator, params)
+	}
 	var exp []int64
 	// Length of the polynomial.
 	pl := polynomialLength(params)
 	// Base.
-	b := int64(Base)
-	// First denominator of the sequence (d^0=1, d^1=10, d^2=100, ...).
-	d := int64(1)
+	b := int64(params.Base())
 	for i := 0; i < pl; i++ {
-		// Numerator (with truncation).
-		// The original code was doing a floor division.
-		// The truncation will have the same effect.
-		n := numerator / d
 		// Symmetric modulo.
-		sm := symmetricModulo(n)
+		sm := symmetricModulo(numerator, b)
 		// Add to the set of expansions.
 		exp = append(exp, sm)
-		if exp[i] < 0 {
-			numerator = numerator + d*b
+		// Remove the digit from the numerator.
+		// The division is exact, so the truncation has no effect on negative numerators.
+		numerator = (numerator - sm) / b
+	}
+	return exp
+}
+
+// standardExpansion expands the absolute value of the numerator with digits in [0, b)
+// and gives every digit the sign of the numerator.
+func standardExpansion(numerator int64, params *Parameters) []int64 {
+	exp := make([]int64, polynomialLength(params))
+	// Base.
+	b := int64(params.Base())
+	for i := range exp {
+		// Go truncates the division, so the remainder has the sign of the numerator.
+		exp[i] = numerator % b
+		numerator /= b
+	}
+	return exp
+}
+
+// nafExpansion expands the numerator in the non-adjacent form of base 2:
+// digits in {-1, 0, 1} such that no two consecutive digits are nonzero.
+func nafExpansion(numerator int64, params *Parameters) []int64 {
+	exp := make([]int64, polynomialLength(params))
+	for i := range exp {
+		if numerator&1 != 0 {
+			// The digit makes the remaining numerator divisible by 4: 2 - (n mod 4).
+			exp[i] = 2 - (numerator & 3)
+			numerator -= exp[i]
 		}
-		d *= b
+		// The numerator is even, so the shift is an exact division.
+		numerator >>= 1
 	}
 	return exp
 }
@@ -53,11 +84,34 @@ func parseRational(rat float64, params *Parameters) int64 {
 	b := float64(params.Base())
 	// Base to the power of minimal power: b^(|p|).
 	bp := math.Pow(b, p)
-	// Rational transformed.
-	n := math.Trunc(rat * bp)
+	// Rational transformed, in units of 1 / (D x b^|p|) where D is the common denominator.
+	n := math.Trunc(rat * bp * float64(params.Denominator()))
 	return int64(n)
 }
 
+// exactNumerator returns the numerator of parseRational as an exact integer, so that rationals
+// whose numerator does not fit in 64 bits are not wrapped around. It returns nil if the rational
+// is not finite.
+func exactNumerator(rat float64, params *Parameters) *big.Int {
+	bp := math.Pow(float64(params.Base()), float64(-params.MinPower()))
+	n := math.Trunc(rat * bp * float64(params.Denominator()))
+	if math.IsInf(n, 0) || math.IsNaN(n) {
+		return nil
+	}
+	i, _ := big.NewFloat(n).Int(nil)
+	return i
+}
+
+// encodeNumerator returns the numerator of parseRational if it is in the message space,
+// and an encoding error with its exact value otherwise.
+func encodeNumerator(rat float64, params *Parameters) (int64, error) {
+	n := exactNumerator(rat, params)
+	if n == nil || !n.IsInt64() || numeratorIsInvalid(n, params) {
+		return 0, newEncodeError(rat, n, params, ErrNumeratorIsNotInTheMessageSpaceRange)
+	}
+	return n.Int64(), nil
+}
+
 func dotProduct(v1 []*big.Rat, v2 []int64) *big.Rat {
 	// Dot product total.
 	dp := big.NewRat(0, 1)
@@ -78,19 +132,91 @@ func rationalToFraction(r float64, params *Parameters) *big.Rat {
 	absP := math.Abs(float64(params.MinPower()))
 	// Base to the power of the absolute value of p.
 	bPowP := math.Pow(float64(params.Base()), absP)
-	// Generate numerator by multiplying rational by b^{|p|} and taking the integer part.
-	n := int64(math.Trunc(r * bPowP))
-	// Generate denominator as b^{|p|}.
-	d := int64(bPowP)
+	// Common denominator.
+	cd := params.Denominator()
+	// Generate numerator by multiplying rational by D x b^{|p|} and taking the integer part.
+	n := int64(math.Trunc(r * bPowP * float64(cd)))
+	// Generate denominator as D x b^{|p|}.
+	d := int64(bPowP) * cd
 	// Generate fraction
 	return big.NewRat(n, d)
 }
 
-// roundUp rounds a float up to p (minimal power) decimal places.
-func roundUp(r float64, params *Parameters) float64 {
+// round rounds a float to the nearest value with p (minimal power) decimal places.
+// Inexact decoded floats can be slightly above or below the exact rational, so rounding
+// up would add one unit of the last decimal place to half of them.
+func round(r float64, params *Parameters) float64 {
 	// Base to the power of the absolute value of p.
 	p := math.Pow(float64(params.Base()), float64(-1*params.MinPower()))
 	// Digit.
 	d := p * r
-	return math.Ceil(d) / p
+	return math.Round(d) / p
+}
+
+// isPowerOfTwo checks if a positive integer is a power of 2.
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// addInt64 adds two integers and reports whether the sum did not overflow.
+func addInt64(a, b int64) (int64, bool) {
+	s := a + b
+	// Overflow happens when both terms have the same sign and the sum has a different one.
+	if (a >= 0) == (b >= 0) && (s >= 0) != (a >= 0) {
+		return 0, false
+	}
+	return s, true
+}
+
+// mulInt64 multiplies two integers and reports whether the product did not overflow.
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	m := a * b
+	// Overflow happens when the division does not recover the factor.
+	if m/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return m, true
+}
+
+// minInt returns the smallest of two integers.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt returns the greatest of two integers.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// fractionNumerator truncates a fraction to p (minimal power) decimal places and returns its numerator,
+// that is, the integer part of f x D x b^(|p|) where D is the common denominator.
+// It also reports whether the numerator fits in a 64-bit integer.
+func fractionNumerator(f *big.Rat, params *Parameters) (int64, bool) {
+	n := truncatedNumerator(f, params)
+	if !n.IsInt64() {
+		return 0, false
+	}
+	return n.Int64(), true
+}
+
+// truncatedNumerator returns the exact integer part of f x D x b^(|p|).
+func truncatedNumerator(f *big.Rat, params *Parameters) *big.Int {
+	n := new(big.Int).Mul(f.Num(), codeDenominator(params))
+	return n.Quo(n, f.Denom())
+}
+
+// codeDenominator returns the denominator of the numerators of the message space: D x b^(|p|).
+func codeDenominator(params *Parameters) *big.Int {
+	// Base to the power of the absolute value of p.
+	bp := new(big.Int).Exp(big.NewInt(int64(params.Base())), big.NewInt(int64(-params.MinPower())), nil)
+	return bp.Mul(bp, big.NewInt(params.Denominator()))
 }
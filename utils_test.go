@@ -1,6 +1,7 @@
 package polyrat
 
 import (
+	"math"
 	"math/big"
 	"testing"
 )
@@ -21,7 +22,7 @@ func TestSymmetricModulo(t *testing.T) {
 
 	for i := 0; i < pl; i++ {
 		n := int64(r / d)
-		m := symmetricModulo(n)
+		m := symmetricModulo(n, Base)
 		if em[i] != m {
 			t.Errorf("expected %d but got %d", em[i], m)
 		}
@@ -72,19 +73,87 @@ func TestRationalToFraction(t *testing.T) {
 	}
 }
 
-func TestRoundUp(t *testing.T) {
-	// Rational value.
-	r := 1097165.2727272727
-	// Expected rounded rational.
-	er := 1097165.273
+func TestRound(t *testing.T) {
 	// Create parameters (p, q, d).
 	params, err := NewParameters(-3, 1, 16)
 	if err != nil {
 		t.Error(err)
 	}
-	// Round up.
-	ru := roundUp(r, params)
-	if ru != er {
-		t.Errorf("expected rational %f but got %f", er, ru)
+	// Rationals and expected rounded rationals.
+	tests := []struct {
+		r, er float64
+	}{
+		{1097165.2727272727, 1097165.273},
+		{1310.5999999999999, 1310.6},
+		{1310.6000000000001, 1310.6},
+		{-1310.5999999999999, -1310.6},
+		{-1310.6000000000001, -1310.6},
+	}
+	for _, test := range tests {
+		rr := round(test.r, params)
+		if rr != test.er {
+			t.Errorf("expected rational %f but got %f", test.er, rr)
+		}
+	}
+}
+
+func TestIsPowerOfTwo(t *testing.T) {
+	// Powers of 2.
+	for _, n := range []int{1, 2, 4, 1024, 32768} {
+		if !isPowerOfTwo(n) {
+			t.Errorf("%d should be a power of 2", n)
+		}
+	}
+	// Not powers of 2.
+	for _, n := range []int{-2, 0, 3, 12, 1000} {
+		if isPowerOfTwo(n) {
+			t.Errorf("%d should not be a power of 2", n)
+		}
+	}
+}
+
+func TestOverflowArithmetic(t *testing.T) {
+	// Check additions.
+	if _, ok := addInt64(math.MaxInt64, 1); ok {
+		t.Error("addition should overflow")
+	}
+	if s, ok := addInt64(math.MaxInt64, -1); !ok || s != math.MaxInt64-1 {
+		t.Errorf("expected sum %d but got %d", int64(math.MaxInt64-1), s)
+	}
+	// Check multiplications.
+	if _, ok := mulInt64(math.MinInt64, -1); ok {
+		t.Error("multiplication should overflow")
+	}
+	if _, ok := mulInt64(1<<32, 1<<31); ok {
+		t.Error("multiplication should overflow")
+	}
+	if m, ok := mulInt64(-1<<31, 1<<32); !ok || m != math.MinInt64 {
+		t.Errorf("expected product %d but got %d", int64(math.MinInt64), m)
+	}
+}
+
+func TestNAFExpansion(t *testing.T) {
+	// Create parameters (p, q, d) with the non-adjacent form.
+	params, err := NewParametersWithDigits(-3, 6, 16, DigitsNAF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every numerator of the message space has a non-adjacent form of q-p+1 digits.
+	lb, ub := messageSpaceBounds(params)
+	for n := int64(lb); n <= int64(ub); n++ {
+		e := expansion(n, params)
+		v := int64(0)
+		for i := len(e) - 1; i >= 0; i-- {
+			if e[i] < -1 || e[i] > 1 {
+				t.Fatalf("%d: digit %d is not in {-1, 0, 1}", n, e[i])
+			}
+			if i > 0 && e[i] != 0 && e[i-1] != 0 {
+				t.Fatalf("%d: digits %d and %d are adjacent nonzero digits", n, i-1, i)
+			}
+			v = 2*v + e[i]
+		}
+		if v != n {
+			t.Fatalf("expected %d but got %d from %v", n, v, e)
+		}
 	}
 }
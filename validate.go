@@ -2,24 +2,69 @@ package polyrat
 
 import (
 	"math"
+	"math/big"
 )
 
-// inputIsInvalid checks if the number given to the function is in the input space.
+// messageSpaceBounds returns the lower and upper bounds of the numerators that can be encoded.
 // We just consider the case when the parity of the base is even.
-func inputIsInvalid(input int64, params *Parameters) bool {
+func messageSpaceBounds(params *Parameters) (float64, float64) {
 	// Define a common component of all bounds: b^(q-p+1) - 1.
 	b, q, p := float64(params.Base()), params.MaxPower(), params.MinPower()
 	// Exponent: e = q-p+1.
 	e := float64(q - p + 1)
 	// b^(q-p+1) - 1
 	bp := math.Pow(b, e) - 1
+	// Standard digits encode every absolute value with at most q-p+1 digits in [0, b).
+	if params.DigitSet() == DigitsStandard {
+		return -bp, bp
+	}
+	// The greatest non-adjacent form of q-p+1 digits is 1010...1 in base 2, that is, floor((2^(q-p+2) - 1) / 3).
+	if params.DigitSet() == DigitsNAF {
+		m := math.Floor((math.Pow(b, e+1) - 1) / 3)
+		return -m, m
+	}
 	// We define the lower and upper bounds by defining the equations in separated parts.
 	// Lower bound: -b/2 x (b^(q-p+1) - 1) / (b-1).
 	lb := ((-b / 2) * bp) / (b - 1)
 	// Upper bound: (b/2 - 1) x ((b^(q-p+1) - 1) / (b-1)).
 	ub := (((b / 2) - 1) * bp) / (b - 1)
-	// Check if number is less than lower bound or greater than upper bound.
-	return float64(input) < lb || ub < float64(input)
+	return lb, ub
+}
+
+// messageSpaceLimits returns the exact lower and upper bounds of the numerators that can be encoded,
+// which messageSpaceBounds only approximates when b^(q-p+1) has more than 53 bits.
+func messageSpaceLimits(params *Parameters) (*big.Int, *big.Int) {
+	b := big.NewInt(int64(params.Base()))
+	one := big.NewInt(1)
+	// b^(q-p+1) - 1.
+	bp := new(big.Int).Exp(b, big.NewInt(int64(params.MaxPower()-params.MinPower()+1)), nil)
+	bp.Sub(bp, one)
+	switch params.DigitSet() {
+	case DigitsStandard:
+		return new(big.Int).Neg(bp), bp
+	case DigitsNAF:
+		// floor((2^(q-p+2) - 1) / 3).
+		m := new(big.Int).Lsh(one, uint(params.MaxPower()-params.MinPower()+2))
+		m.Sub(m, one).Quo(m, big.NewInt(3))
+		return new(big.Int).Neg(m), m
+	}
+	// (b^(q-p+1) - 1) / (b-1), which is exact.
+	r := new(big.Int).Quo(bp, new(big.Int).Sub(b, one))
+	h := new(big.Int).Rsh(b, 1)
+	lb := new(big.Int).Mul(new(big.Int).Neg(h), r)
+	ub := new(big.Int).Mul(h.Sub(h, one), r)
+	return lb, ub
+}
+
+// numeratorIsInvalid checks if an exact numerator is outside of the message space.
+func numeratorIsInvalid(n *big.Int, params *Parameters) bool {
+	lb, ub := messageSpaceLimits(params)
+	return n.Cmp(lb) < 0 || ub.Cmp(n) < 0
+}
+
+// inputIsInvalid checks if the number given to the function is in the input space.
+func inputIsInvalid(input int64, params *Parameters) bool {
+	return numeratorIsInvalid(big.NewInt(input), params)
 }
 
 func validateDegreeOfCode(code []int64, params *Parameters) error {
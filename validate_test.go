@@ -24,6 +24,29 @@ func TestValidateInput(t *testing.T) {
 	}
 }
 
+func TestMessageSpaceBounds(t *testing.T) {
+	tests := []struct {
+		digits int
+		lb, ub float64
+	}{
+		{DigitsBalanced, -555555, 444444},
+		{DigitsStandard, -999999, 999999},
+		// 101010 in base 2.
+		{DigitsNAF, -42, 42},
+	}
+	for _, test := range tests {
+		// Create parameters (p, q, d).
+		params, err := NewParametersWithDigits(-2, 3, 16, test.digits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lb, ub := messageSpaceBounds(params)
+		if lb != test.lb || ub != test.ub {
+			t.Errorf("%s: expected [%.0f, %.0f] but got [%.0f, %.0f]", params.String(), test.lb, test.ub, lb, ub)
+		}
+	}
+}
+
 func TestValidateDegreeOfCode(t *testing.T) {
 	// Check if an error is thrown when the degree of the code is not a power of 2.
 	// Input code.
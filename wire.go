@@ -0,0 +1,240 @@
+package polyrat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// WireMagic is the header that identifies a container of an encoded value.
+const WireMagic = "PRAT"
+
+// WireVersion is the current version of the wire format.
+const WireVersion = 1
+
+// Coefficient encodings of a container.
+const (
+	EncodingDense  = iota // EncodingDense stores the d coefficients as 64-bit integers.
+	EncodingSparse        // EncodingSparse stores the nonzero coefficients as (index, coefficient) pairs.
+	EncodingPacked        // EncodingPacked stores the digits bit-packed with MarshalCode.
+)
+
+// wireHeaderLength is the number of bytes of the header: magic, version, encoding,
+// digit set, base, lower power, higher power, degree, common denominator and payload length.
+const wireHeaderLength = len(WireMagic) + 1 + 1 + 1 + 4*4 + 8 + 4
+
+// Container is a self-describing and versioned representation of a code.
+// The wire format is, in big-endian order:
+//
+//	magic "PRAT" | version (1 byte) | encoding (1 byte) | digit set (1 byte) |
+//	b, p, q, d (4 bytes each) | common denominator (8 bytes) | payload length (4 bytes) | payload |
+//	CRC-32 (IEEE) of all the previous bytes (4 bytes)
+//
+// The digit set is one of DigitsBalanced, DigitsStandard and DigitsNAF, and the base
+// should be the base of the digit set. The common denominator is 1 for parameters without one.
+// Dense payloads have d 8-byte coefficients, sparse payloads have a 4-byte count followed by
+// 4-byte indices and 8-byte coefficients, and packed payloads are generated by MarshalCode.
+type Container struct {
+	Params   *Parameters // Params are the parameters used to generate the code.
+	Code     []int64     // Code is the encoded value.
+	Encoding int         // Encoding is the encoding of the coefficients.
+}
+
+// NewContainer creates a container for a code generated with the given parameters.
+func NewContainer(code []int64, params *Parameters, encoding int) (*Container, error) {
+	// Validate degree of code.
+	err := validateDegreeOfCode(code, params)
+	if err != nil {
+		return nil, err
+	}
+	if encoding < EncodingDense || encoding > EncodingPacked {
+		return nil, ErrWireEncodingIsInvalid
+	}
+	container := new(Container)
+	container.Params = params
+	container.Code = code
+	container.Encoding = encoding
+	return container, nil
+}
+
+// Write writes the container in the wire format.
+func (container *Container) Write(w io.Writer) error {
+	payload, err := container.payload()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	params := container.Params
+	buf.WriteString(WireMagic)
+	buf.WriteByte(WireVersion)
+	buf.WriteByte(byte(container.Encoding))
+	buf.WriteByte(byte(params.DigitSet()))
+	for _, v := range []int{params.Base(), params.MinPower(), params.MaxPower(), params.Degree()} {
+		binary.Write(&buf, binary.BigEndian, int32(v))
+	}
+	binary.Write(&buf, binary.BigEndian, params.Denominator())
+	binary.Write(&buf, binary.BigEndian, uint32(len(payload)))
+	buf.Write(payload)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(buf.Bytes()))
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Read reads a container in the wire format.
+// Truncated or corrupted data is detected before the code is decoded.
+func (container *Container) Read(r io.Reader) error {
+	// Header.
+	header := make([]byte, wireHeaderLength)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return wireReadError(err)
+	}
+	if string(header[:len(WireMagic)]) != WireMagic {
+		return ErrWireMagicIsInvalid
+	}
+	h := header[len(WireMagic):]
+	if h[0] != WireVersion {
+		return ErrWireVersionIsNotSupported
+	}
+	encoding := int(h[1])
+	digits := int(h[2])
+	b := int(int32(binary.BigEndian.Uint32(h[3:])))
+	p := int(int32(binary.BigEndian.Uint32(h[7:])))
+	q := int(int32(binary.BigEndian.Uint32(h[11:])))
+	d := int(int32(binary.BigEndian.Uint32(h[15:])))
+	den := int64(binary.BigEndian.Uint64(h[19:]))
+	l := binary.BigEndian.Uint32(h[27:])
+	if d > MaxDegree {
+		return ErrDIsGreaterThanMaxDegree
+	}
+	params, err := NewParametersWithDigits(p, q, d, digits)
+	if err != nil {
+		return err
+	}
+	// The base is given by the digit set.
+	if b != params.Base() {
+		return ErrBaseIsNotSupported
+	}
+	params, err = params.WithDenominator(den)
+	if err != nil {
+		return err
+	}
+	// The payload length is checked before allocating it.
+	if encoding < EncodingDense || encoding > EncodingPacked {
+		return ErrWireEncodingIsInvalid
+	}
+	if uint64(l) > uint64(maxPayloadLength(encoding, params)) {
+		return ErrWirePayloadIsInvalid
+	}
+	// Payload and checksum, read through a limited reader so that the buffer only
+	// grows with the bytes that actually arrive, whatever the length claims.
+	var buf bytes.Buffer
+	n, err := buf.ReadFrom(io.LimitReader(r, int64(l)+4))
+	if err != nil {
+		return wireReadError(err)
+	}
+	if n < int64(l)+4 {
+		return ErrWireIsTruncated
+	}
+	rest := buf.Bytes()
+	payload := rest[:l]
+	checksum := crc32.ChecksumIEEE(append(header, payload...))
+	if checksum != binary.BigEndian.Uint32(rest[l:]) {
+		return ErrWireChecksumIsInvalid
+	}
+	code, err := parsePayload(payload, encoding, params)
+	if err != nil {
+		return err
+	}
+	container.Params = params
+	container.Code = code
+	container.Encoding = encoding
+	return nil
+}
+
+// payload encodes the coefficients of the container.
+func (container *Container) payload() ([]byte, error) {
+	var buf bytes.Buffer
+	switch container.Encoding {
+	case EncodingDense:
+		for _, c := range container.Code {
+			binary.Write(&buf, binary.BigEndian, c)
+		}
+	case EncodingSparse:
+		var n uint32
+		for _, c := range container.Code {
+			if c != 0 {
+				n++
+			}
+		}
+		binary.Write(&buf, binary.BigEndian, n)
+		for i, c := range container.Code {
+			if c != 0 {
+				binary.Write(&buf, binary.BigEndian, uint32(i))
+				binary.Write(&buf, binary.BigEndian, c)
+			}
+		}
+	case EncodingPacked:
+		return MarshalCode(container.Code, container.Params)
+	default:
+		return nil, ErrWireEncodingIsInvalid
+	}
+	return buf.Bytes(), nil
+}
+
+// parsePayload decodes the coefficients of a payload.
+func parsePayload(payload []byte, encoding int, params *Parameters) ([]int64, error) {
+	d := params.Degree()
+	switch encoding {
+	case EncodingDense:
+		if len(payload) != 8*d {
+			return nil, ErrWirePayloadIsInvalid
+		}
+		code := make([]int64, d)
+		for i := 0; i < d; i++ {
+			code[i] = int64(binary.BigEndian.Uint64(payload[8*i:]))
+		}
+		return code, nil
+	case EncodingSparse:
+		if len(payload) < 4 {
+			return nil, ErrWirePayloadIsInvalid
+		}
+		n := int(binary.BigEndian.Uint32(payload))
+		if n > d || len(payload) != 4+12*n {
+			return nil, ErrWirePayloadIsInvalid
+		}
+		code := make([]int64, d)
+		for i := 0; i < n; i++ {
+			idx := int(binary.BigEndian.Uint32(payload[4+12*i:]))
+			if idx >= d {
+				return nil, ErrWirePayloadIsInvalid
+			}
+			code[idx] = int64(binary.BigEndian.Uint64(payload[8+12*i:]))
+		}
+		return code, nil
+	default:
+		return UnmarshalCode(payload, params)
+	}
+}
+
+// maxPayloadLength returns the greatest payload length of an encoding.
+func maxPayloadLength(encoding int, params *Parameters) int {
+	switch encoding {
+	case EncodingDense:
+		return 8 * params.Degree()
+	case EncodingSparse:
+		return 4 + 12*params.Degree()
+	default:
+		return PackedCodeLength(params)
+	}
+}
+
+// wireReadError reports truncated data as ErrWireIsTruncated.
+func wireReadError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrWireIsTruncated
+	}
+	return err
+}
@@ -0,0 +1,218 @@
+package polyrat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestContainerRoundTrip(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-8, 20, 2048)
+	if err != nil {
+		t.Error(err)
+	}
+	c, err := Encode(-83740034.866, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Payload lengths of each encoding.
+	nonzero := 0
+	for _, v := range c {
+		if v != 0 {
+			nonzero++
+		}
+	}
+	lengths := []int{8 * 2048, 4 + 12*nonzero, 15}
+	for encoding := EncodingDense; encoding <= EncodingPacked; encoding++ {
+		container, err := NewContainer(c, params, encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		err = container.Write(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.Len() != wireHeaderLength+lengths[encoding]+4 {
+			t.Errorf("encoding %d: expected %d bytes but got %d", encoding, wireHeaderLength+lengths[encoding]+4, buf.Len())
+		}
+		read := new(Container)
+		err = read.Read(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read.Encoding != encoding || read.Params.String() != params.String() {
+			t.Errorf("expected encoding %d with (%s) but got %d with (%s)", encoding, params.String(), read.Encoding, read.Params.String())
+		}
+		for i := 0; i < len(c); i++ {
+			if read.Code[i] != c[i] {
+				t.Fatalf("encoding %d: expected code value %d at position %d but got %d", encoding, c[i], i, read.Code[i])
+			}
+		}
+	}
+}
+
+func TestContainerDigitSets(t *testing.T) {
+	for _, digits := range []int{DigitsStandard, DigitsNAF} {
+		// Create parameters (p, q, d) with the digit set.
+		params, err := NewParametersWithDigits(-2, 5, 16, digits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := Encode(-3.75, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		container, err := NewContainer(c, params, EncodingPacked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		err = container.Write(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The header gives back the digit set.
+		read := new(Container)
+		err = read.Read(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read.Params.String() != params.String() {
+			t.Errorf("expected (%s) but got (%s)", params.String(), read.Params.String())
+		}
+		dr, err := Decode(read.Code, read.Params)
+		if err != nil || dr != -3.75 {
+			t.Errorf("%s: expected %f but got %f (%v)", params.String(), -3.75, dr, err)
+		}
+	}
+}
+
+func TestContainerDenominator(t *testing.T) {
+	// Create parameters (p, q, d) with a common denominator.
+	params, err := NewParameters(-2, 5, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err = params.WithDenominator(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1/3 is encoded exactly.
+	c, err := EncodeRational(1, 3, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container, err := NewContainer(c, params, EncodingDense)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = container.Write(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	read := new(Container)
+	err = read.Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Params.Denominator() != 3 {
+		t.Errorf("expected denominator %d but got %d", 3, read.Params.Denominator())
+	}
+	f, err := DecodeExact(read.Code, read.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Cmp(big.NewRat(1, 3)) != 0 {
+		t.Errorf("expected 1/3 but got %s", f.RatString())
+	}
+	// Check that an error is thrown when the common denominator is less than one.
+	corrupted := append([]byte{}, data...)
+	binary.BigEndian.PutUint64(corrupted[len(WireMagic)+19:], 0)
+	err = new(Container).Read(bytes.NewReader(corrupted))
+	if !errors.Is(err, ErrCommonDenominatorIsLessThanOne) {
+		t.Errorf("expected error %v but got %v", ErrCommonDenominatorIsLessThanOne, err)
+	}
+}
+
+func TestContainerCorruption(t *testing.T) {
+	// Create parameters (p, q, d).
+	params, err := NewParameters(-4, 11, 16)
+	if err != nil {
+		t.Error(err)
+	}
+	c := []int64{4, 2, 1, -2, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 5, 5}
+	container, err := NewContainer(c, params, EncodingSparse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = container.Write(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	// Check that truncated data is detected.
+	err = new(Container).Read(bytes.NewReader(data[:len(data)-1]))
+	if !errors.Is(err, ErrWireIsTruncated) {
+		t.Errorf("expected error %v but got %v", ErrWireIsTruncated, err)
+	}
+	// Check that a corrupted coefficient is detected.
+	corrupted := append([]byte{}, data...)
+	corrupted[wireHeaderLength+12] ^= 1
+	err = new(Container).Read(bytes.NewReader(corrupted))
+	if !errors.Is(err, ErrWireChecksumIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrWireChecksumIsInvalid, err)
+	}
+	// Check that an invalid magic header is detected.
+	corrupted = append([]byte{}, data...)
+	corrupted[0] = 'X'
+	err = new(Container).Read(bytes.NewReader(corrupted))
+	if !errors.Is(err, ErrWireMagicIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrWireMagicIsInvalid, err)
+	}
+	// Check that an unknown version is detected.
+	corrupted = append([]byte{}, data...)
+	corrupted[len(WireMagic)] = WireVersion + 1
+	err = new(Container).Read(bytes.NewReader(corrupted))
+	if !errors.Is(err, ErrWireVersionIsNotSupported) {
+		t.Errorf("expected error %v but got %v", ErrWireVersionIsNotSupported, err)
+	}
+	// Check that an unsupported base is rejected.
+	corrupted = append([]byte{}, data...)
+	binary.BigEndian.PutUint32(corrupted[len(WireMagic)+3:], 3)
+	err = new(Container).Read(bytes.NewReader(corrupted))
+	if !errors.Is(err, ErrBaseIsNotSupported) {
+		t.Errorf("expected error %v but got %v", ErrBaseIsNotSupported, err)
+	}
+	// Check that an unknown digit set is rejected.
+	corrupted = append([]byte{}, data...)
+	corrupted[len(WireMagic)+2] = 7
+	err = new(Container).Read(bytes.NewReader(corrupted))
+	if !errors.Is(err, ErrDigitSetIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrDigitSetIsInvalid, err)
+	}
+	// Check that a huge degree is rejected before reading the payload.
+	corrupted = append([]byte{}, data...)
+	binary.BigEndian.PutUint32(corrupted[len(WireMagic)+15:], 1<<30)
+	err = new(Container).Read(bytes.NewReader(corrupted))
+	if !errors.Is(err, ErrDIsGreaterThanMaxDegree) {
+		t.Errorf("expected error %v but got %v", ErrDIsGreaterThanMaxDegree, err)
+	}
+	// Check that a payload shorter than its length is detected.
+	corrupted = append([]byte{}, data[:wireHeaderLength]...)
+	err = new(Container).Read(bytes.NewReader(corrupted))
+	if !errors.Is(err, ErrWireIsTruncated) {
+		t.Errorf("expected error %v but got %v", ErrWireIsTruncated, err)
+	}
+	// Check that an invalid encoding is rejected.
+	_, err = NewContainer(c, params, 7)
+	if !errors.Is(err, ErrWireEncodingIsInvalid) {
+		t.Errorf("expected error %v but got %v", ErrWireEncodingIsInvalid, err)
+	}
+}